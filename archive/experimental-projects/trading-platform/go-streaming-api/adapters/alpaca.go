@@ -0,0 +1,210 @@
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// alpacaFeedURL returns the authenticated market data stream URL for
+// feed, e.g. "iex" (free tier) or "sip" (paid, consolidated).
+func alpacaFeedURL(feed string) string {
+	return fmt.Sprintf("wss://stream.data.alpaca.markets/v2/%s", feed)
+}
+
+// AlpacaAdapter ingests trades, quotes, and minute bars from Alpaca's
+// authenticated market data WebSocket.
+type AlpacaAdapter struct {
+	url    string
+	apiKey string
+	secret string
+
+	mu      sync.Mutex
+	conn    *websocket.Conn
+	symbols []string
+	cancel  context.CancelFunc
+	closed  bool
+
+	events chan MarketData
+}
+
+// NewAlpacaAdapter creates an Alpaca adapter authenticated with key and
+// secret, streaming from feed (typically "iex" or "sip").
+func NewAlpacaAdapter(feed, key, secret string) *AlpacaAdapter {
+	return &AlpacaAdapter{
+		url:    alpacaFeedURL(feed),
+		apiKey: key,
+		secret: secret,
+		events: make(chan MarketData, 1024),
+	}
+}
+
+// Connect starts the reconnect-with-backoff dial loop in the
+// background, authenticating on every (re)connect before resubscribing
+// to any previously requested symbols.
+func (a *AlpacaAdapter) Connect(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	a.cancel = cancel
+	go dialLoop(ctx, "alpaca", a.url, a.onOpen, a.onMessage)
+	return nil
+}
+
+func (a *AlpacaAdapter) onOpen(conn *websocket.Conn) error {
+	conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+
+	// Alpaca sends an unsolicited "connected" control message first.
+	if _, _, err := conn.ReadMessage(); err != nil {
+		return fmt.Errorf("alpaca: waiting for connected message: %w", err)
+	}
+
+	auth := struct {
+		Action string `json:"action"`
+		Key    string `json:"key"`
+		Secret string `json:"secret"`
+	}{Action: "auth", Key: a.apiKey, Secret: a.secret}
+	data, err := json.Marshal(auth)
+	if err != nil {
+		return err
+	}
+	if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+		return err
+	}
+
+	_, resp, err := conn.ReadMessage()
+	if err != nil {
+		return fmt.Errorf("alpaca: waiting for auth response: %w", err)
+	}
+	if !bytes.Contains(resp, []byte("authenticated")) {
+		return fmt.Errorf("alpaca: authentication failed: %s", resp)
+	}
+
+	a.mu.Lock()
+	a.conn = conn
+	symbols := append([]string(nil), a.symbols...)
+	a.mu.Unlock()
+
+	if len(symbols) == 0 {
+		return nil
+	}
+	return a.sendSubscribe(conn, symbols)
+}
+
+// Subscribe requests trades, quotes, and minute bars for symbols,
+// remembering them so they survive a reconnect.
+func (a *AlpacaAdapter) Subscribe(symbols []string) error {
+	a.mu.Lock()
+	a.symbols = append(a.symbols, symbols...)
+	conn := a.conn
+	a.mu.Unlock()
+
+	if conn == nil {
+		return nil // sent by onOpen once authenticated
+	}
+	return a.sendSubscribe(conn, symbols)
+}
+
+func (a *AlpacaAdapter) sendSubscribe(conn *websocket.Conn, symbols []string) error {
+	msg := struct {
+		Action string   `json:"action"`
+		Trades []string `json:"trades"`
+		Quotes []string `json:"quotes"`
+		Bars   []string `json:"bars"`
+	}{Action: "subscribe", Trades: symbols, Quotes: symbols, Bars: symbols}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+	return conn.WriteMessage(websocket.TextMessage, data)
+}
+
+// alpacaMessage covers the union of trade ("t"), quote ("q"), and
+// minute-bar ("b") messages Alpaca multiplexes onto the same stream.
+type alpacaMessage struct {
+	Type     string  `json:"T"`
+	Symbol   string  `json:"S"`
+	Price    float64 `json:"p"`
+	Size     float64 `json:"s"`
+	BidPrice float64 `json:"bp"`
+	BidSize  float64 `json:"bs"`
+	AskPrice float64 `json:"ap"`
+	AskSize  float64 `json:"as"`
+	Open     float64 `json:"o"`
+	High     float64 `json:"h"`
+	Low      float64 `json:"l"`
+	Close    float64 `json:"c"`
+	Volume   float64 `json:"v"`
+	Time     string  `json:"t"`
+}
+
+func (a *AlpacaAdapter) onMessage(raw []byte) {
+	var msgs []alpacaMessage
+	if err := json.Unmarshal(raw, &msgs); err != nil {
+		return
+	}
+
+	for _, m := range msgs {
+		ts, _ := time.Parse(time.RFC3339Nano, m.Time)
+
+		switch m.Type {
+		case "t":
+			a.emit(m.Symbol, "trade", Trade{Price: m.Price, Quantity: m.Size, Timestamp: ts})
+		case "q":
+			a.emit(m.Symbol, "quote", Quote{BidPrice: m.BidPrice, BidSize: m.BidSize, AskPrice: m.AskPrice, AskSize: m.AskSize})
+		case "b":
+			a.emit(m.Symbol, "candle", Candle{Open: m.Open, High: m.High, Low: m.Low, Close: m.Close, Volume: m.Volume, StartTime: ts})
+		default:
+			// subscription ack, error, or other control message
+		}
+	}
+}
+
+func (a *AlpacaAdapter) emit(symbol, kind string, data interface{}) {
+	MessagesTotal.WithLabelValues("alpaca", kind).Inc()
+	event := MarketData{Source: "alpaca", Type: kind, Symbol: symbol, Data: data, Timestamp: time.Now()}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed {
+		return
+	}
+	select {
+	case a.events <- event:
+	default:
+		// consumer is behind; drop rather than block the read loop
+	}
+}
+
+// Events returns the channel normalized trades, quotes, and bars are
+// delivered on. It is closed when the adapter is Closed.
+func (a *AlpacaAdapter) Events() <-chan MarketData {
+	return a.events
+}
+
+// Close stops the dial loop, closes the active connection if any, and
+// closes the events channel so a consumer ranging over Events() exits.
+func (a *AlpacaAdapter) Close() error {
+	if a.cancel != nil {
+		a.cancel()
+	}
+	a.mu.Lock()
+	conn := a.conn
+	a.conn = nil
+	alreadyClosed := a.closed
+	a.closed = true
+	a.mu.Unlock()
+
+	if !alreadyClosed {
+		close(a.events)
+	}
+	if conn != nil {
+		return conn.Close()
+	}
+	return nil
+}
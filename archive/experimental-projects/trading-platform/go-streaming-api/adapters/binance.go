@@ -0,0 +1,189 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const binanceDefaultURL = "wss://stream.binance.com:9443/stream"
+
+// BinanceAdapter ingests trade prints from Binance's combined-stream
+// WebSocket endpoint and normalizes them into MarketData events.
+type BinanceAdapter struct {
+	url string
+
+	mu      sync.Mutex
+	conn    *websocket.Conn
+	symbols []string
+	nextID  int
+	cancel  context.CancelFunc
+	closed  bool
+
+	events chan MarketData
+}
+
+// NewBinanceAdapter creates a Binance adapter against the production
+// combined-stream endpoint.
+func NewBinanceAdapter() *BinanceAdapter {
+	return &BinanceAdapter{
+		url:    binanceDefaultURL,
+		events: make(chan MarketData, 1024),
+	}
+}
+
+// Connect starts the reconnect-with-backoff dial loop in the background
+// and returns immediately; Events() begins delivering once a connection
+// is established.
+func (b *BinanceAdapter) Connect(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	b.cancel = cancel
+	go dialLoop(ctx, "binance", b.url, b.onOpen, b.onMessage)
+	return nil
+}
+
+// onOpen resubscribes to every previously requested symbol whenever a
+// new connection (first or reconnected) is established.
+func (b *BinanceAdapter) onOpen(conn *websocket.Conn) error {
+	b.mu.Lock()
+	b.conn = conn
+	symbols := append([]string(nil), b.symbols...)
+	b.mu.Unlock()
+
+	if len(symbols) == 0 {
+		return nil
+	}
+	return b.sendSubscribe(conn, symbols)
+}
+
+// Subscribe requests trade updates for symbols, remembering them so
+// they survive a reconnect.
+func (b *BinanceAdapter) Subscribe(symbols []string) error {
+	b.mu.Lock()
+	b.symbols = append(b.symbols, symbols...)
+	conn := b.conn
+	b.mu.Unlock()
+
+	if conn == nil {
+		return nil // sent by onOpen once the connection is up
+	}
+	return b.sendSubscribe(conn, symbols)
+}
+
+func (b *BinanceAdapter) sendSubscribe(conn *websocket.Conn, symbols []string) error {
+	streams := make([]string, len(symbols))
+	for i, s := range symbols {
+		streams[i] = strings.ToLower(s) + "@trade"
+	}
+
+	b.mu.Lock()
+	b.nextID++
+	id := b.nextID
+	b.mu.Unlock()
+
+	msg := struct {
+		Method string   `json:"method"`
+		Params []string `json:"params"`
+		ID     int      `json:"id"`
+	}{Method: "SUBSCRIBE", Params: streams, ID: id}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+	return conn.WriteMessage(websocket.TextMessage, data)
+}
+
+// binanceTradeEnvelope is the combined-stream wrapper Binance puts
+// around every message: {"stream": "btcusdt@trade", "data": {...}}.
+type binanceTradeEnvelope struct {
+	Stream string          `json:"stream"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// binanceTrade is the payload of a single <symbol>@trade stream message.
+type binanceTrade struct {
+	Symbol       string `json:"s"`
+	Price        string `json:"p"`
+	Quantity     string `json:"q"`
+	TradeTimeMs  int64  `json:"T"`
+	IsBuyerMaker bool   `json:"m"`
+}
+
+func (b *BinanceAdapter) onMessage(raw []byte) {
+	var envelope binanceTradeEnvelope
+	if err := json.Unmarshal(raw, &envelope); err != nil || envelope.Stream == "" {
+		return // subscribe ack or unrecognized control message
+	}
+
+	var trade binanceTrade
+	if err := json.Unmarshal(envelope.Data, &trade); err != nil {
+		return
+	}
+
+	price, _ := strconv.ParseFloat(trade.Price, 64)
+	quantity, _ := strconv.ParseFloat(trade.Quantity, 64)
+	side := "buy"
+	if trade.IsBuyerMaker {
+		side = "sell"
+	}
+
+	event := MarketData{
+		Source: "binance",
+		Type:   "trade",
+		Symbol: strings.ToUpper(trade.Symbol),
+		Data: Trade{
+			Price:     price,
+			Quantity:  quantity,
+			Side:      side,
+			Timestamp: time.UnixMilli(trade.TradeTimeMs),
+		},
+		Timestamp: time.Now(),
+	}
+
+	MessagesTotal.WithLabelValues("binance", "trade").Inc()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	select {
+	case b.events <- event:
+	default:
+		// consumer is behind; drop rather than block the read loop
+	}
+}
+
+// Events returns the channel normalized trades are delivered on. It is
+// closed when the adapter is Closed.
+func (b *BinanceAdapter) Events() <-chan MarketData {
+	return b.events
+}
+
+// Close stops the dial loop, closes the active connection if any, and
+// closes the events channel so a consumer ranging over Events() exits.
+func (b *BinanceAdapter) Close() error {
+	if b.cancel != nil {
+		b.cancel()
+	}
+	b.mu.Lock()
+	conn := b.conn
+	b.conn = nil
+	alreadyClosed := b.closed
+	b.closed = true
+	b.mu.Unlock()
+
+	if !alreadyClosed {
+		close(b.events)
+	}
+	if conn != nil {
+		return conn.Close()
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+// Package adapters normalizes market data from upstream exchanges into
+// this package's own MarketData envelope carrying a Trade, Quote, or
+// Candle, so the rest of the service never has to know whether a given
+// symbol's data came from Binance, Alpaca, or anywhere else. It does not
+// produce order book snapshots, and its types are separate from the
+// main package's Trade/OrderBook/MarketData types.
+package adapters
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// MarketDataSource is implemented by every upstream ingestor.
+type MarketDataSource interface {
+	// Connect dials the upstream feed. Close is final: once an adapter
+	// is Closed, its Events channel stays closed even if Connect is
+	// called again.
+	Connect(ctx context.Context) error
+	// Subscribe requests updates for symbols on the already-connected feed.
+	Subscribe(symbols []string) error
+	// Events returns the channel normalized updates are pushed onto. It
+	// is closed when the adapter is Closed.
+	Events() <-chan MarketData
+	// Close tears down the upstream connection.
+	Close() error
+}
+
+// MarketData is a single normalized trade, quote, or candle from an
+// upstream source.
+type MarketData struct {
+	Source    string      `json:"source"`
+	Type      string      `json:"type"` // "trade", "quote", "candle"
+	Symbol    string      `json:"symbol"`
+	Data      interface{} `json:"data"`
+	Timestamp time.Time   `json:"timestamp"`
+}
+
+// Trade is a normalized upstream trade print.
+type Trade struct {
+	Price     float64   `json:"price"`
+	Quantity  float64   `json:"quantity"`
+	Side      string    `json:"side"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Quote is a normalized top-of-book bid/ask.
+type Quote struct {
+	BidPrice float64 `json:"bid_price"`
+	BidSize  float64 `json:"bid_size"`
+	AskPrice float64 `json:"ask_price"`
+	AskSize  float64 `json:"ask_size"`
+}
+
+// Candle is a normalized OHLCV bar.
+type Candle struct {
+	Open      float64   `json:"open"`
+	High      float64   `json:"high"`
+	Low       float64   `json:"low"`
+	Close     float64   `json:"close"`
+	Volume    float64   `json:"volume"`
+	StartTime time.Time `json:"start_time"`
+}
+
+// Metrics shared by every adapter, labeled by source so operators can
+// tell upstream feeds apart on a single dashboard.
+var (
+	MessagesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "trading_adapter_messages_total",
+			Help: "Total number of messages received from upstream market data adapters",
+		},
+		[]string{"source", "type"},
+	)
+	ReconnectsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "trading_adapter_reconnects_total",
+			Help: "Total number of reconnect attempts made by upstream market data adapters",
+		},
+		[]string{"source"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(MessagesTotal)
+	prometheus.MustRegister(ReconnectsTotal)
+}
+
+// backoff is a small exponential-backoff-with-jitter helper shared by
+// every adapter's reconnect loop.
+type backoff struct {
+	attempt int
+	min     time.Duration
+	max     time.Duration
+}
+
+func newBackoff(min, max time.Duration) *backoff {
+	return &backoff{min: min, max: max}
+}
+
+// Next returns how long to wait before the next reconnect attempt and
+// advances the backoff state.
+func (b *backoff) Next() time.Duration {
+	d := float64(b.min) * math.Pow(2, float64(b.attempt))
+	if d > float64(b.max) {
+		d = float64(b.max)
+	}
+	b.attempt++
+	jitter := 0.5 + rand.Float64()/2 // 50%-100% of the computed delay
+	return time.Duration(d * jitter)
+}
+
+// Reset clears the backoff state after a successful, stable connection.
+func (b *backoff) Reset() {
+	b.attempt = 0
+}
+
+// dialLoop connects to url, calling onOpen on every new connection and
+// onMessage for every data message it receives, and keeps reconnecting
+// with exponential backoff (after a failed dial, a failed onOpen, or a
+// broken connection) until ctx is cancelled.
+func dialLoop(ctx context.Context, source, url string, onOpen func(*websocket.Conn) error, onMessage func([]byte)) {
+	bo := newBackoff(time.Second, 30*time.Second)
+
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+
+		conn, _, err := websocket.DefaultDialer.DialContext(ctx, url, nil)
+		if err != nil {
+			ReconnectsTotal.WithLabelValues(source).Inc()
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(bo.Next()):
+				continue
+			}
+		}
+
+		if onOpen != nil {
+			if err := onOpen(conn); err != nil {
+				conn.Close()
+				ReconnectsTotal.WithLabelValues(source).Inc()
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(bo.Next()):
+					continue
+				}
+			}
+		}
+
+		bo.Reset()
+		readLoop(ctx, conn, onMessage)
+
+		conn.Close()
+		if ctx.Err() != nil {
+			return
+		}
+		ReconnectsTotal.WithLabelValues(source).Inc()
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(bo.Next()):
+		}
+	}
+}
+
+// readLoop pumps frames off conn until it errors or ctx is cancelled,
+// keeping the connection alive with periodic WebSocket ping frames.
+func readLoop(ctx context.Context, conn *websocket.Conn, onMessage func([]byte)) {
+	conn.SetPongHandler(func(string) error {
+		conn.SetReadDeadline(time.Now().Add(60 * time.Second))
+		return nil
+	})
+	conn.SetReadDeadline(time.Now().Add(60 * time.Second))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			onMessage(data)
+		}
+	}()
+
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-done:
+			return
+		case <-ticker.C:
+			conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,232 @@
+// Package candles aggregates the matching engine's trade stream into
+// OHLCV candles per symbol across several fixed periods, keeping a
+// rolling in-memory history and periodically flushing closed candles to
+// Redis for longer-term, cross-replica retention.
+package candles
+
+import (
+	"sync"
+	"time"
+)
+
+// Candle is a single OHLCV bar. Closed is false while the bar is still
+// accumulating trades; it flips to true once a trade lands in the next
+// period and the bar is pushed into the ring.
+type Candle struct {
+	Symbol    string    `json:"symbol"`
+	Period    string    `json:"period"`
+	OpenTime  time.Time `json:"open_time"`
+	CloseTime time.Time `json:"close_time"`
+	Open      float64   `json:"open"`
+	High      float64   `json:"high"`
+	Low       float64   `json:"low"`
+	Close     float64   `json:"close"`
+	Volume    float64   `json:"volume"`
+	Closed    bool      `json:"closed"`
+}
+
+// Period is one of the fixed candle resolutions this package maintains.
+type Period struct {
+	Name     string
+	Duration time.Duration
+}
+
+// Periods lists every resolution aggregated for each symbol.
+var Periods = []Period{
+	{"1m", time.Minute},
+	{"5m", 5 * time.Minute},
+	{"15m", 15 * time.Minute},
+	{"1h", time.Hour},
+	{"4h", 4 * time.Hour},
+	{"1d", 24 * time.Hour},
+}
+
+// UpdateFunc is called with the affected candle every time a trade
+// updates it, and again (Closed == true) the moment it's closed out —
+// the natural hook for pushing kline.<period>@<symbol> WebSocket frames.
+type UpdateFunc func(c Candle)
+
+// Aggregator maintains, per symbol and period, a rolling ring of closed
+// candles plus the current in-progress one.
+type Aggregator struct {
+	mu       sync.Mutex
+	capacity int
+	onUpdate UpdateFunc
+	symbols  map[string]map[string]*series // symbol -> period name -> series
+	pending  []Candle                      // closed candles not yet flushed to Redis
+}
+
+// NewAggregator creates an aggregator keeping capacity closed candles
+// per symbol/period in memory. onUpdate may be nil.
+func NewAggregator(capacity int, onUpdate UpdateFunc) *Aggregator {
+	return &Aggregator{
+		capacity: capacity,
+		onUpdate: onUpdate,
+		symbols:  make(map[string]map[string]*series),
+	}
+}
+
+// series is one symbol's rolling history for a single period.
+type series struct {
+	duration time.Duration
+	ring     []Candle
+	next     int
+	size     int
+	current  *Candle
+}
+
+func (a *Aggregator) seriesFor(symbol string) map[string]*series {
+	byPeriod, ok := a.symbols[symbol]
+	if !ok {
+		byPeriod = make(map[string]*series)
+		for _, p := range Periods {
+			byPeriod[p.Name] = &series{duration: p.Duration, ring: make([]Candle, a.capacity)}
+		}
+		a.symbols[symbol] = byPeriod
+	}
+	return byPeriod
+}
+
+// OnTrade folds a single trade print into every period's candle for
+// symbol, closing and starting bars as period boundaries are crossed,
+// and re-emitting the affected bar for trades that arrive late (ts
+// falls in an already-closed bucket still held in the ring).
+func (a *Aggregator) OnTrade(symbol string, price, quantity float64, ts time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	byPeriod := a.seriesFor(symbol)
+	for _, p := range Periods {
+		s := byPeriod[p.Name]
+		s.applyTrade(symbol, p.Name, price, quantity, ts, a)
+	}
+}
+
+func (s *series) applyTrade(symbol, period string, price, quantity float64, ts time.Time, a *Aggregator) {
+	bucketStart := ts.Truncate(s.duration)
+
+	switch {
+	case s.current == nil:
+		s.current = newCandle(symbol, period, bucketStart, s.duration, price, quantity)
+		a.emit(*s.current)
+
+	case bucketStart.Equal(s.current.OpenTime):
+		s.current.applyTick(price, quantity)
+		a.emit(*s.current)
+
+	case bucketStart.Before(s.current.OpenTime):
+		// Late trade for an already-closed bucket still in the ring.
+		if c, ok := s.updateClosed(bucketStart, price, quantity); ok {
+			a.emit(c)
+		}
+
+	default:
+		closed := *s.current
+		closed.Closed = true
+		s.pushClosed(closed)
+		a.emit(closed)
+		a.queueFlush(closed)
+
+		s.current = newCandle(symbol, period, bucketStart, s.duration, price, quantity)
+		a.emit(*s.current)
+	}
+}
+
+func newCandle(symbol, period string, openTime time.Time, duration time.Duration, price, quantity float64) *Candle {
+	return &Candle{
+		Symbol:    symbol,
+		Period:    period,
+		OpenTime:  openTime,
+		CloseTime: openTime.Add(duration),
+		Open:      price,
+		High:      price,
+		Low:       price,
+		Close:     price,
+		Volume:    quantity,
+	}
+}
+
+func (c *Candle) applyTick(price, quantity float64) {
+	if price > c.High {
+		c.High = price
+	}
+	if price < c.Low {
+		c.Low = price
+	}
+	c.Close = price
+	c.Volume += quantity
+}
+
+func (s *series) pushClosed(c Candle) {
+	s.ring[s.next] = c
+	s.next = (s.next + 1) % len(s.ring)
+	if s.size < len(s.ring) {
+		s.size++
+	}
+}
+
+func (s *series) updateClosed(openTime time.Time, price, quantity float64) (Candle, bool) {
+	oldest := (s.next - s.size + len(s.ring)) % len(s.ring)
+	for i := 0; i < s.size; i++ {
+		idx := (oldest + i) % len(s.ring)
+		if s.ring[idx].OpenTime.Equal(openTime) {
+			s.ring[idx].applyTick(price, quantity)
+			return s.ring[idx], true
+		}
+	}
+	return Candle{}, false
+}
+
+func (a *Aggregator) emit(c Candle) {
+	if a.onUpdate != nil {
+		a.onUpdate(c)
+	}
+}
+
+func (a *Aggregator) queueFlush(c Candle) {
+	a.pending = append(a.pending, c)
+}
+
+// Query returns up to limit candles for symbol/period within
+// [start, end] (zero values meaning unbounded), oldest first, including
+// the current in-progress candle if it falls in range. It only
+// consults in-memory state; callers wanting history older than the
+// ring's retention should fall back to Redis (see FlushPending).
+func (a *Aggregator) Query(symbol, period string, start, end time.Time, limit int) []Candle {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	byPeriod, ok := a.symbols[symbol]
+	if !ok {
+		return nil
+	}
+	s, ok := byPeriod[period]
+	if !ok {
+		return nil
+	}
+
+	var all []Candle
+	oldest := (s.next - s.size + len(s.ring)) % len(s.ring)
+	for i := 0; i < s.size; i++ {
+		all = append(all, s.ring[(oldest+i)%len(s.ring)])
+	}
+	if s.current != nil {
+		all = append(all, *s.current)
+	}
+
+	filtered := all[:0]
+	for _, c := range all {
+		if !start.IsZero() && c.OpenTime.Before(start) {
+			continue
+		}
+		if !end.IsZero() && c.OpenTime.After(end) {
+			continue
+		}
+		filtered = append(filtered, c)
+	}
+
+	if limit > 0 && len(filtered) > limit {
+		filtered = filtered[len(filtered)-limit:]
+	}
+	return filtered
+}
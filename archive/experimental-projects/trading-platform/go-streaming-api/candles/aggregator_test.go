@@ -0,0 +1,161 @@
+package candles
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOnTradeStartsNewCandle(t *testing.T) {
+	a := NewAggregator(4, nil)
+	ts := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	a.OnTrade("BTCUSD", 100, 1, ts)
+
+	s := a.symbols["BTCUSD"]["1m"]
+	if s.current == nil {
+		t.Fatal("expected a current candle to be started")
+	}
+	if s.current.Open != 100 || s.current.High != 100 || s.current.Low != 100 || s.current.Close != 100 || s.current.Volume != 1 {
+		t.Fatalf("expected a fresh OHLCV seeded from the first trade, got %+v", s.current)
+	}
+	if !s.current.OpenTime.Equal(ts) {
+		t.Fatalf("expected OpenTime truncated to the bucket start, got %v", s.current.OpenTime)
+	}
+}
+
+func TestOnTradeSameBucketUpdatesHighLowCloseVolume(t *testing.T) {
+	a := NewAggregator(4, nil)
+	t1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	a.OnTrade("BTCUSD", 100, 1, t1)
+	a.OnTrade("BTCUSD", 105, 2, t1.Add(10*time.Second))
+	a.OnTrade("BTCUSD", 95, 1, t1.Add(20*time.Second))
+
+	s := a.symbols["BTCUSD"]["1m"]
+	c := s.current
+	if c.Open != 100 {
+		t.Fatalf("expected Open to stay at the first trade's price, got %v", c.Open)
+	}
+	if c.High != 105 {
+		t.Fatalf("expected High to track the highest trade price, got %v", c.High)
+	}
+	if c.Low != 95 {
+		t.Fatalf("expected Low to track the lowest trade price, got %v", c.Low)
+	}
+	if c.Close != 95 {
+		t.Fatalf("expected Close to be the most recent trade price, got %v", c.Close)
+	}
+	if c.Volume != 4 {
+		t.Fatalf("expected Volume to sum every trade's quantity, got %v", c.Volume)
+	}
+	if c.Closed {
+		t.Fatal("expected the in-progress candle to stay open within its own bucket")
+	}
+}
+
+func TestOnTradeCrossingBoundaryClosesAndStartsFreshBar(t *testing.T) {
+	var emitted []Candle
+	a := NewAggregator(4, func(c Candle) {
+		if c.Period == "1m" {
+			emitted = append(emitted, c)
+		}
+	})
+	t1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Minute)
+
+	a.OnTrade("BTCUSD", 100, 1, t1)
+	a.OnTrade("BTCUSD", 105, 2, t1.Add(30*time.Second))
+	a.OnTrade("BTCUSD", 90, 1, t2)
+
+	s := a.symbols["BTCUSD"]["1m"]
+	if s.size != 1 {
+		t.Fatalf("expected exactly one closed candle pushed into the ring, got size=%d", s.size)
+	}
+	closed := s.ring[0]
+	if !closed.Closed {
+		t.Fatal("expected the pushed candle to be marked Closed")
+	}
+	if closed.Open != 100 || closed.High != 105 || closed.Low != 100 || closed.Close != 105 || closed.Volume != 3 {
+		t.Fatalf("expected the closed bar to hold the first bucket's OHLCV, got %+v", closed)
+	}
+	if !closed.OpenTime.Equal(t1) {
+		t.Fatalf("expected the closed bar's OpenTime to be the first bucket, got %v", closed.OpenTime)
+	}
+
+	if s.current == nil || s.current.Closed {
+		t.Fatal("expected a fresh, still-open candle for the new bucket")
+	}
+	if !s.current.OpenTime.Equal(t2) {
+		t.Fatalf("expected the fresh candle's OpenTime to be the new bucket, got %v", s.current.OpenTime)
+	}
+	if s.current.Open != 90 || s.current.High != 90 || s.current.Low != 90 || s.current.Close != 90 || s.current.Volume != 1 {
+		t.Fatalf("expected the fresh candle seeded from the boundary-crossing trade, got %+v", s.current)
+	}
+
+	if len(emitted) != 4 {
+		t.Fatalf("expected an emit for each of the two trades in the first bucket, the close, and the new bar, got %d: %+v", len(emitted), emitted)
+	}
+	if !emitted[2].Closed || !emitted[2].OpenTime.Equal(t1) {
+		t.Fatalf("expected the third emit to be the closed first bucket, got %+v", emitted[2])
+	}
+}
+
+func TestOnTradeLateTradeUpdatesCorrectClosedBucketWithoutCorruptingNeighbor(t *testing.T) {
+	a := NewAggregator(4, nil)
+	t1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Minute)
+	t3 := t2.Add(time.Minute)
+
+	a.OnTrade("BTCUSD", 100, 1, t1)
+	a.OnTrade("BTCUSD", 105, 2, t1.Add(30*time.Second)) // closes into t1 bucket: O100 H105 L100 C105 V3
+	a.OnTrade("BTCUSD", 90, 1, t2)                      // closes into t2 bucket: O90 H90 L90 C90 V1
+	a.OnTrade("BTCUSD", 200, 5, t3)                     // both t1 and t2 buckets now sit in the ring
+
+	// Late trade lands back in the t1 bucket, which is no longer current.
+	a.OnTrade("BTCUSD", 50, 10, t1.Add(45*time.Second))
+
+	s := a.symbols["BTCUSD"]["1m"]
+	var bucket1, bucket2 *Candle
+	oldest := (s.next - s.size + len(s.ring)) % len(s.ring)
+	for i := 0; i < s.size; i++ {
+		c := &s.ring[(oldest+i)%len(s.ring)]
+		switch {
+		case c.OpenTime.Equal(t1):
+			bucket1 = c
+		case c.OpenTime.Equal(t2):
+			bucket2 = c
+		}
+	}
+	if bucket1 == nil || bucket2 == nil {
+		t.Fatalf("expected both the t1 and t2 buckets to still be in the ring, got size=%d", s.size)
+	}
+
+	if bucket1.Low != 50 || bucket1.Close != 50 || bucket1.Volume != 13 || bucket1.High != 105 {
+		t.Fatalf("expected the late trade to fold into the t1 bucket, got %+v", bucket1)
+	}
+	if bucket2.Open != 90 || bucket2.High != 90 || bucket2.Low != 90 || bucket2.Close != 90 || bucket2.Volume != 1 {
+		t.Fatalf("expected the neighboring t2 bucket to be untouched by a late trade targeting t1, got %+v", bucket2)
+	}
+}
+
+func TestOnTradeLateTradeOutsideRingIsDropped(t *testing.T) {
+	a := NewAggregator(1, nil)
+	t1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Minute)
+	t3 := t2.Add(time.Minute)
+
+	a.OnTrade("BTCUSD", 100, 1, t1)
+	a.OnTrade("BTCUSD", 90, 1, t2)  // pushes the t1 bucket into a ring of size 1
+	a.OnTrade("BTCUSD", 80, 1, t3) // evicts the t1 bucket, leaving only t2 closed
+
+	// A trade for the now-evicted t1 bucket must not be mistaken for t2.
+	a.OnTrade("BTCUSD", 50, 1, t1.Add(15*time.Second))
+
+	s := a.symbols["BTCUSD"]["1m"]
+	if s.size != 1 || !s.ring[0].OpenTime.Equal(t2) {
+		t.Fatalf("expected only the t2 bucket to remain in the ring, got %+v", s.ring[:s.size])
+	}
+	if s.ring[0].Low != 90 || s.ring[0].Volume != 1 {
+		t.Fatalf("expected the evicted late trade to leave the t2 bucket untouched, got %+v", s.ring[0])
+	}
+}
@@ -0,0 +1,80 @@
+package candles
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisKey returns the sorted-set key closed candles for symbol/period
+// are stored under, scored by open time so range queries are cheap.
+func redisKey(symbol, period string) string {
+	return fmt.Sprintf("candles:%s:%s", symbol, period)
+}
+
+// FlushPending drains every closed candle queued since the last flush
+// and writes it to its symbol/period's Redis sorted set. It's meant to
+// be called on a ticker (e.g. every few seconds) rather than per-trade,
+// so Redis only sees periodic batches, not one write per closed bar.
+func (a *Aggregator) FlushPending(ctx context.Context, rdb *redis.Client) error {
+	a.mu.Lock()
+	pending := a.pending
+	a.pending = nil
+	a.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	pipe := rdb.Pipeline()
+	for _, c := range pending {
+		data, err := json.Marshal(c)
+		if err != nil {
+			return fmt.Errorf("candles: marshal %s %s candle: %w", c.Symbol, c.Period, err)
+		}
+		pipe.ZAdd(ctx, redisKey(c.Symbol, c.Period), redis.Z{
+			Score:  float64(c.OpenTime.Unix()),
+			Member: data,
+		})
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// QueryRedis returns closed candles for symbol/period within
+// [start, end] from Redis, for history older than what the in-memory
+// ring still retains.
+func QueryRedis(ctx context.Context, rdb *redis.Client, symbol, period string, start, end time.Time, limit int) ([]Candle, error) {
+	min, max := "-inf", "+inf"
+	if !start.IsZero() {
+		min = fmt.Sprintf("%d", start.Unix())
+	}
+	if !end.IsZero() {
+		max = fmt.Sprintf("%d", end.Unix())
+	}
+
+	raw, err := rdb.ZRangeByScore(ctx, redisKey(symbol, period), &redis.ZRangeBy{
+		Min: min,
+		Max: max,
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("candles: query redis: %w", err)
+	}
+
+	candlesOut := make([]Candle, 0, len(raw))
+	for _, item := range raw {
+		var c Candle
+		if err := json.Unmarshal([]byte(item), &c); err != nil {
+			continue
+		}
+		candlesOut = append(candlesOut, c)
+	}
+
+	if limit > 0 && len(candlesOut) > limit {
+		candlesOut = candlesOut[len(candlesOut)-limit:]
+	}
+	return candlesOut, nil
+}
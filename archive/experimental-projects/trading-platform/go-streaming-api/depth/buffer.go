@@ -0,0 +1,208 @@
+// Package depth maintains, per symbol, a running L2 order book snapshot
+// plus a ring buffer of sequenced incremental diffs. It is the backing
+// store for the book.snapshot@<symbol> and book.update@<symbol>
+// WebSocket channels: new subscribers get the full Snapshot, and
+// existing subscribers who fall behind can be told to resnapshot
+// instead of replaying a diff they can no longer make sense of.
+package depth
+
+import (
+	"sync"
+	"time"
+)
+
+// Level is a single aggregated price/quantity pair.
+type Level struct {
+	Price    float64 `json:"price"`
+	Quantity float64 `json:"quantity"`
+}
+
+// Snapshot is the full L2 state of a symbol's book at a point in time.
+type Snapshot struct {
+	Symbol    string    `json:"symbol"`
+	Seq       uint64    `json:"seq"`
+	Bids      []Level   `json:"bids"`
+	Asks      []Level   `json:"asks"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Update is an incremental diff between two sequence numbers. Bids/Asks
+// only contain levels that changed since PrevSeq; a level with
+// Quantity 0 means it was removed.
+type Update struct {
+	Symbol    string    `json:"symbol"`
+	Seq       uint64    `json:"seq"`
+	PrevSeq   uint64    `json:"prev_seq"`
+	Bids      []Level   `json:"bids"`
+	Asks      []Level   `json:"asks"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+const defaultRingSize = 256
+
+// Buffer tracks one symbol's running snapshot and its recent history of
+// diffs, so a client that already has seq N can ask "what changed since
+// N" instead of re-downloading the whole book.
+type Buffer struct {
+	mu       sync.RWMutex
+	symbol   string
+	snapshot Snapshot
+	ring     []Update
+	next     int // next write index into ring
+	size     int // number of valid entries in ring
+}
+
+// NewBuffer creates an empty buffer for symbol with the given ring
+// capacity (how many past updates are kept for gap resync).
+func NewBuffer(symbol string, capacity int) *Buffer {
+	if capacity <= 0 {
+		capacity = defaultRingSize
+	}
+	return &Buffer{
+		symbol:   symbol,
+		snapshot: Snapshot{Symbol: symbol},
+		ring:     make([]Update, capacity),
+	}
+}
+
+// SetSnapshot replaces the buffer's running snapshot wholesale and
+// returns it. Used to seed the buffer, e.g. from the matching engine's
+// current book, the first time a symbol is touched.
+func (b *Buffer) SetSnapshot(bids, asks []Level, seq uint64) Snapshot {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.snapshot = Snapshot{
+		Symbol:    b.symbol,
+		Seq:       seq,
+		Bids:      bids,
+		Asks:      asks,
+		Timestamp: time.Now(),
+	}
+	return b.snapshot
+}
+
+// PushUpdate diffs the book's new full state (bids, asks) against the
+// buffer's running snapshot, records the resulting Update in the ring,
+// advances the running snapshot to match, and returns the Update.
+func (b *Buffer) PushUpdate(bids, asks []Level, seq uint64) Update {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	update := Update{
+		Symbol:    b.symbol,
+		Seq:       seq,
+		PrevSeq:   b.snapshot.Seq,
+		Bids:      diffLevels(b.snapshot.Bids, bids, bidLess),
+		Asks:      diffLevels(b.snapshot.Asks, asks, askLess),
+		Timestamp: time.Now(),
+	}
+
+	b.snapshot.Seq = seq
+	b.snapshot.Bids = bids
+	b.snapshot.Asks = asks
+	b.snapshot.Timestamp = update.Timestamp
+
+	b.ring[b.next] = update
+	b.next = (b.next + 1) % len(b.ring)
+	if b.size < len(b.ring) {
+		b.size++
+	}
+
+	return update
+}
+
+// bidLess and askLess order two price levels the same way the book
+// itself does on each side, so diffLevels can merge-walk old and new
+// without knowing which side it's looking at.
+func bidLess(a, b float64) bool { return a > b }
+func askLess(a, b float64) bool { return a < b }
+
+// diffLevels merge-walks old and new, both sorted by less, and returns
+// only the levels that changed: added or repriced levels carry their new
+// quantity, and a level present in old but absent from new is emitted
+// with Quantity 0 to signal removal.
+func diffLevels(old, new []Level, less func(a, b float64) bool) []Level {
+	var out []Level
+	i, j := 0, 0
+	for i < len(old) || j < len(new) {
+		switch {
+		case j >= len(new) || (i < len(old) && less(old[i].Price, new[j].Price)):
+			out = append(out, Level{Price: old[i].Price, Quantity: 0})
+			i++
+		case i >= len(old) || less(new[j].Price, old[i].Price):
+			out = append(out, new[j])
+			j++
+		default:
+			if old[i].Quantity != new[j].Quantity {
+				out = append(out, new[j])
+			}
+			i++
+			j++
+		}
+	}
+	return out
+}
+
+// Snapshot returns a copy of the buffer's current running snapshot.
+func (b *Buffer) Snapshot() Snapshot {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.snapshot
+}
+
+// Since returns every update after lastSeq, oldest first. ok is false
+// if lastSeq has already fallen out of the ring (or is otherwise
+// unreachable), in which case the caller must send a full Snapshot
+// instead of replaying diffs.
+func (b *Buffer) Since(lastSeq uint64) (updates []Update, ok bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if lastSeq == b.snapshot.Seq {
+		return nil, true
+	}
+
+	// Walk the ring oldest-to-newest collecting anything after lastSeq.
+	oldest := (b.next - b.size + len(b.ring)) % len(b.ring)
+	found := false
+	for i := 0; i < b.size; i++ {
+		u := b.ring[(oldest+i)%len(b.ring)]
+		if u.PrevSeq == lastSeq || found {
+			found = true
+			updates = append(updates, u)
+		}
+	}
+	if !found {
+		return nil, false
+	}
+	return updates, true
+}
+
+// Registry is a set of per-symbol buffers, created lazily on first use.
+type Registry struct {
+	mu       sync.Mutex
+	capacity int
+	buffers  map[string]*Buffer
+}
+
+// NewRegistry creates a registry whose buffers each keep capacity past
+// updates.
+func NewRegistry(capacity int) *Registry {
+	return &Registry{
+		capacity: capacity,
+		buffers:  make(map[string]*Buffer),
+	}
+}
+
+// Get returns the buffer for symbol, creating it if this is the first
+// time the symbol has been seen.
+func (r *Registry) Get(symbol string) *Buffer {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b, ok := r.buffers[symbol]
+	if !ok {
+		b = NewBuffer(symbol, r.capacity)
+		r.buffers[symbol] = b
+	}
+	return b
+}
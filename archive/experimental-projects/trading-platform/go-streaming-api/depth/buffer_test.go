@@ -0,0 +1,83 @@
+package depth
+
+import "testing"
+
+func TestDiffLevelsAddedRemovedRepricedUnchanged(t *testing.T) {
+	old := []Level{
+		{Price: 100, Quantity: 1}, // unchanged
+		{Price: 99, Quantity: 2},  // repriced (quantity changes)
+		{Price: 98, Quantity: 3},  // removed
+	}
+	new := []Level{
+		{Price: 101, Quantity: 5}, // added
+		{Price: 100, Quantity: 1}, // unchanged
+		{Price: 99, Quantity: 4},  // repriced
+	}
+
+	got := diffLevels(old, new, bidLess)
+
+	want := []Level{
+		{Price: 101, Quantity: 5},
+		{Price: 99, Quantity: 4},
+		{Price: 98, Quantity: 0},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d changed levels, got %+v", len(want), got)
+	}
+	for i, w := range want {
+		if got[i] != w {
+			t.Fatalf("level %d: expected %+v, got %+v", i, w, got[i])
+		}
+	}
+}
+
+func TestDiffLevelsNoChanges(t *testing.T) {
+	levels := []Level{{Price: 100, Quantity: 1}, {Price: 99, Quantity: 2}}
+	if got := diffLevels(levels, levels, bidLess); len(got) != 0 {
+		t.Fatalf("expected no diff between identical books, got %+v", got)
+	}
+}
+
+func TestBufferSinceWithinRing(t *testing.T) {
+	b := NewBuffer("BTCUSD", 8)
+	b.SetSnapshot(nil, nil, 1)
+
+	b.PushUpdate([]Level{{Price: 100, Quantity: 1}}, nil, 2)
+	b.PushUpdate([]Level{{Price: 100, Quantity: 2}}, nil, 3)
+	b.PushUpdate([]Level{{Price: 100, Quantity: 3}}, nil, 4)
+
+	updates, ok := b.Since(2)
+	if !ok {
+		t.Fatalf("expected a gap within the ring to resolve, got ok=false")
+	}
+	if len(updates) != 2 {
+		t.Fatalf("expected 2 updates after seq 2, got %d: %+v", len(updates), updates)
+	}
+	if updates[0].Seq != 3 || updates[1].Seq != 4 {
+		t.Fatalf("expected updates in seq order 3,4, got %+v", updates)
+	}
+}
+
+func TestBufferSinceAlreadyCurrent(t *testing.T) {
+	b := NewBuffer("BTCUSD", 8)
+	b.SetSnapshot(nil, nil, 1)
+	b.PushUpdate([]Level{{Price: 100, Quantity: 1}}, nil, 2)
+
+	updates, ok := b.Since(2)
+	if !ok || len(updates) != 0 {
+		t.Fatalf("expected no updates when already caught up, got updates=%+v ok=%v", updates, ok)
+	}
+}
+
+func TestBufferSinceGapFallenOutOfRing(t *testing.T) {
+	b := NewBuffer("BTCUSD", 2)
+	b.SetSnapshot(nil, nil, 1)
+
+	b.PushUpdate([]Level{{Price: 100, Quantity: 1}}, nil, 2)
+	b.PushUpdate([]Level{{Price: 100, Quantity: 2}}, nil, 3)
+	b.PushUpdate([]Level{{Price: 100, Quantity: 3}}, nil, 4) // evicts the PrevSeq=1 entry
+
+	if _, ok := b.Since(1); ok {
+		t.Fatalf("expected Since to report a gap once lastSeq has fallen out of the ring")
+	}
+}
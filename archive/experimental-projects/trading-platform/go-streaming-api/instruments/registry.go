@@ -0,0 +1,191 @@
+// Package instruments holds per-symbol tick-size and contract metadata
+// and the validation rules that orders must satisfy before they reach
+// the matching engine.
+package instruments
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisKey is the hash that instrument definitions are kept in when
+// loaded from Redis: field = symbol, value = JSON-encoded InstrumentInfo.
+const redisKey = "instruments"
+
+// InstrumentInfo describes the tradable increments and limits for a
+// single symbol.
+type InstrumentInfo struct {
+	Symbol        string     `json:"symbol"`
+	TickSize      float64    `json:"tick_size"`    // minimum price increment
+	LotSize       float64    `json:"lot_size"`     // minimum quantity increment
+	MinNotional   float64    `json:"min_notional"` // minimum price*quantity
+	ContractValue float64    `json:"contract_value,omitempty"`
+	Expiry        *time.Time `json:"expiry,omitempty"` // futures-style instruments only
+}
+
+// Registry is a set of InstrumentInfo keyed by symbol.
+type Registry struct {
+	mu          sync.RWMutex
+	instruments map[string]InstrumentInfo
+}
+
+// NewRegistry returns a registry seeded with a small set of
+// sane defaults so the API isn't validating against nothing out of the
+// box; call LoadFromRedis or Set to override them.
+func NewRegistry() *Registry {
+	r := &Registry{
+		instruments: make(map[string]InstrumentInfo),
+	}
+	for _, info := range Defaults() {
+		r.instruments[info.Symbol] = info
+	}
+	return r
+}
+
+// Defaults returns a small set of common instruments used to seed a
+// fresh Registry before config or Redis has been consulted.
+func Defaults() []InstrumentInfo {
+	return []InstrumentInfo{
+		{Symbol: "BTCUSD", TickSize: 0.5, LotSize: 0.0001, MinNotional: 10},
+		{Symbol: "ETHUSD", TickSize: 0.05, LotSize: 0.001, MinNotional: 10},
+	}
+}
+
+// Set adds or replaces an instrument's metadata.
+func (r *Registry) Set(info InstrumentInfo) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.instruments[info.Symbol] = info
+}
+
+// Get returns the instrument for symbol, if known.
+func (r *Registry) Get(symbol string) (InstrumentInfo, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	info, ok := r.instruments[symbol]
+	return info, ok
+}
+
+// List returns all known instruments.
+func (r *Registry) List() []InstrumentInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]InstrumentInfo, 0, len(r.instruments))
+	for _, info := range r.instruments {
+		out = append(out, info)
+	}
+	return out
+}
+
+// LoadFromRedis adds or overwrites an entry for every symbol stored in
+// the "instruments" hash. Entries for symbols not in the hash (e.g. the
+// defaults) are kept.
+func (r *Registry) LoadFromRedis(ctx context.Context, rdb *redis.Client) error {
+	fields, err := rdb.HGetAll(ctx, redisKey).Result()
+	if err != nil {
+		return fmt.Errorf("instruments: load from redis: %w", err)
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	loaded := make(map[string]InstrumentInfo, len(fields))
+	for symbol, raw := range fields {
+		var info InstrumentInfo
+		if err := json.Unmarshal([]byte(raw), &info); err != nil {
+			return fmt.Errorf("instruments: decode %s: %w", symbol, err)
+		}
+		loaded[symbol] = info
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for symbol, info := range loaded {
+		r.instruments[symbol] = info
+	}
+	return nil
+}
+
+// RoundPrice rounds price to the nearest valid tick for symbol, or
+// returns it unchanged if symbol is unknown.
+func (r *Registry) RoundPrice(symbol string, price float64) float64 {
+	info, ok := r.Get(symbol)
+	if !ok || info.TickSize <= 0 {
+		return price
+	}
+	return roundToStep(price, info.TickSize)
+}
+
+// RoundQuantity rounds quantity down to the nearest valid lot for
+// symbol, or returns it unchanged if symbol is unknown.
+func (r *Registry) RoundQuantity(symbol string, quantity float64) float64 {
+	info, ok := r.Get(symbol)
+	if !ok || info.LotSize <= 0 {
+		return quantity
+	}
+	return math.Floor(quantity/info.LotSize) * info.LotSize
+}
+
+func roundToStep(value, step float64) float64 {
+	return math.Round(value/step) * step
+}
+
+var (
+	// ErrUnknownSymbol is returned by Validate for a symbol with no
+	// registered instrument.
+	ErrUnknownSymbol = errors.New("instruments: unknown symbol")
+	// ErrPriceNotTickAligned is returned when an order's price isn't a
+	// multiple of the instrument's tick size.
+	ErrPriceNotTickAligned = errors.New("instruments: price is not aligned to tick size")
+	// ErrQuantityNotLotAligned is returned when an order's quantity isn't
+	// a multiple of the instrument's lot size.
+	ErrQuantityNotLotAligned = errors.New("instruments: quantity is not aligned to lot size")
+	// ErrBelowMinNotional is returned when price*quantity falls below the
+	// instrument's minimum notional.
+	ErrBelowMinNotional = errors.New("instruments: order notional is below the minimum")
+)
+
+// epsilon absorbs float64 rounding noise when checking tick/lot alignment.
+const epsilon = 1e-9
+
+// Validate checks that price and quantity are aligned to symbol's tick
+// and lot sizes and clear its minimum notional. Market orders pass
+// isMarket == true to skip the tick-size and min-notional checks, since
+// the fill price isn't known until the order matches.
+func (r *Registry) Validate(symbol string, price, quantity float64, isMarket bool) error {
+	info, ok := r.Get(symbol)
+	if !ok {
+		return ErrUnknownSymbol
+	}
+
+	if info.LotSize > 0 {
+		steps := quantity / info.LotSize
+		if math.Abs(steps-math.Round(steps)) > epsilon {
+			return ErrQuantityNotLotAligned
+		}
+	}
+
+	if isMarket {
+		return nil
+	}
+
+	if info.TickSize > 0 {
+		steps := price / info.TickSize
+		if math.Abs(steps-math.Round(steps)) > epsilon {
+			return ErrPriceNotTickAligned
+		}
+	}
+
+	if info.MinNotional > 0 && price*quantity < info.MinNotional {
+		return ErrBelowMinNotional
+	}
+
+	return nil
+}
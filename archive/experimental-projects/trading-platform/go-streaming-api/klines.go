@@ -0,0 +1,90 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"trading-platform/go-streaming-api/candles"
+)
+
+// validPeriod reports whether period is one of the resolutions the
+// candles package actually maintains.
+func validPeriod(period string) bool {
+	for _, p := range candles.Periods {
+		if p.Name == period {
+			return true
+		}
+	}
+	return false
+}
+
+// getKlines returns OHLCV candles for symbol/period within [start, end],
+// merging the in-memory ring with Redis for history the ring has already
+// evicted. period defaults to "1m"; start/end are Unix seconds.
+func (te *TradingEngine) getKlines(c *gin.Context) {
+	symbol := c.Param("symbol")
+	period := c.DefaultQuery("period", "1m")
+	if !validPeriod(period) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unsupported period: " + period})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "500"))
+	if err != nil || limit <= 0 {
+		limit = 500
+	}
+
+	var start, end time.Time
+	if s := c.Query("start"); s != "" {
+		if sec, err := strconv.ParseInt(s, 10, 64); err == nil {
+			start = time.Unix(sec, 0)
+		}
+	}
+	if e := c.Query("end"); e != "" {
+		if sec, err := strconv.ParseInt(e, 10, 64); err == nil {
+			end = time.Unix(sec, 0)
+		}
+	}
+
+	result := te.candles.Query(symbol, period, start, end, limit)
+
+	if len(result) < limit {
+		ctx := c.Request.Context()
+		historical, err := candles.QueryRedis(ctx, te.redis, symbol, period, start, end, limit)
+		if err != nil {
+			te.logger.Warn("failed to query candle history from redis:", err)
+		} else {
+			result = mergeCandles(historical, result, limit)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"symbol": symbol, "period": period, "candles": result})
+}
+
+// mergeCandles combines older Redis-backed history with the in-memory
+// tail, de-duplicating by open time and preferring the in-memory copy
+// (it may still be open or have absorbed a late trade Redis hasn't seen).
+func mergeCandles(older, recent []candles.Candle, limit int) []candles.Candle {
+	byOpen := make(map[int64]candles.Candle, len(older)+len(recent))
+	for _, c := range older {
+		byOpen[c.OpenTime.Unix()] = c
+	}
+	for _, c := range recent {
+		byOpen[c.OpenTime.Unix()] = c
+	}
+
+	merged := make([]candles.Candle, 0, len(byOpen))
+	for _, c := range byOpen {
+		merged = append(merged, c)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].OpenTime.Before(merged[j].OpenTime) })
+
+	if limit > 0 && len(merged) > limit {
+		merged = merged[len(merged)-limit:]
+	}
+	return merged
+}
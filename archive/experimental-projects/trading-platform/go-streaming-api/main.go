@@ -3,12 +3,13 @@ package main
 import (
 	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
 	"strconv"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -21,6 +22,12 @@ import (
 	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
+
+	"trading-platform/go-streaming-api/candles"
+	"trading-platform/go-streaming-api/depth"
+	"trading-platform/go-streaming-api/instruments"
+	"trading-platform/go-streaming-api/matching"
+	"trading-platform/go-streaming-api/risk"
 )
 
 // Trading data structures
@@ -36,13 +43,13 @@ type Order struct {
 }
 
 type Trade struct {
-	ID           uint64    `json:"id"`
-	Symbol       string    `json:"symbol"`
-	BuyOrderID   uint64    `json:"buy_order_id"`
-	SellOrderID  uint64    `json:"sell_order_id"`
-	Price        float64   `json:"price"`
-	Quantity     float64   `json:"quantity"`
-	Timestamp    time.Time `json:"timestamp"`
+	ID          uint64    `json:"id"`
+	Symbol      string    `json:"symbol"`
+	BuyOrderID  uint64    `json:"buy_order_id"`
+	SellOrderID uint64    `json:"sell_order_id"`
+	Price       float64   `json:"price"`
+	Quantity    float64   `json:"quantity"`
+	Timestamp   time.Time `json:"timestamp"`
 }
 
 type OrderBook struct {
@@ -52,6 +59,7 @@ type OrderBook struct {
 	LastPrice   float64     `json:"last_price"`
 	Spread      float64     `json:"spread"`
 	TotalVolume uint64      `json:"total_volume"`
+	SeqNo       uint64      `json:"seq_no"`
 	Timestamp   time.Time   `json:"timestamp"`
 }
 
@@ -62,17 +70,57 @@ type MarketData struct {
 	Timestamp time.Time   `json:"timestamp"`
 }
 
+// topicMessage is an outbound message tagged with the topic it was
+// published on, e.g. "book.update@BTCUSD" or "trade@BTCUSD". Topics of
+// the form "<kind>@<symbol>" are only fanned out to clients subscribed
+// to that symbol; topics with no "@" are fanned out to everyone.
+type topicMessage struct {
+	topic string
+	data  []byte
+}
+
+// topicSymbol splits a topic into its symbol suffix, if any.
+func topicSymbol(topic string) (symbol string, scoped bool) {
+	i := strings.LastIndex(topic, "@")
+	if i < 0 {
+		return "", false
+	}
+	return topic[i+1:], true
+}
+
+// topicKind returns the metric-label-friendly prefix of a topic, e.g.
+// "book.update" for "book.update@BTCUSD" — the symbol itself must never
+// become a label value or the metric's cardinality grows per symbol.
+func topicKind(topic string) string {
+	if i := strings.LastIndex(topic, "@"); i >= 0 {
+		return topic[:i]
+	}
+	return topic
+}
+
 // WebSocket connection manager
 type Hub struct {
 	clients    map[*Client]bool
-	broadcast  chan []byte
+	broadcast  chan topicMessage
 	register   chan *Client
 	unregister chan *Client
 	mu         sync.RWMutex
 }
 
+// Publish marshals v and fans it out to clients subscribed to topic's
+// symbol (or to everyone, if topic isn't symbol-scoped).
+func (h *Hub) Publish(topic string, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		log.Printf("hub: failed to marshal message for topic %s: %v", topic, err)
+		return
+	}
+	h.broadcast <- topicMessage{topic: topic, data: data}
+}
+
 type Client struct {
 	hub        *Hub
+	te         *TradingEngine
 	conn       *websocket.Conn
 	send       chan []byte
 	userID     uint32
@@ -122,9 +170,17 @@ func init() {
 
 // Trading engine integration
 type TradingEngine struct {
-	redis  *redis.Client
-	logger *logrus.Logger
-	hub    *Hub
+	redis       *redis.Client
+	logger      *logrus.Logger
+	hub         *Hub
+	redisHub    *RedisHub // non-nil once Redis is confirmed reachable, see main()
+	engine      *matching.Engine
+	depth       *depth.Registry
+	instruments *instruments.Registry
+	bookCache   *bookCache
+	candles     *candles.Aggregator
+	risk        *risk.Guard
+	positions   *risk.PositionTracker
 }
 
 func NewTradingEngine() *TradingEngine {
@@ -141,17 +197,47 @@ func NewTradingEngine() *TradingEngine {
 	})
 
 	hub := &Hub{
-		broadcast:  make(chan []byte, 10000),
+		broadcast:  make(chan topicMessage, 10000),
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
 		clients:    make(map[*Client]bool),
 	}
 
-	return &TradingEngine{
-		redis:  rdb,
-		logger: logger,
-		hub:    hub,
+	te := &TradingEngine{
+		redis:       rdb,
+		logger:      logger,
+		hub:         hub,
+		engine:      matching.NewEngine(),
+		depth:       depth.NewRegistry(256),
+		instruments: instruments.NewRegistry(),
+		bookCache:   newBookCache(200 * time.Millisecond),
+		risk:        risk.NewGuard(rdb, risk.DefaultLimits()),
+		positions:   risk.NewPositionTracker(),
 	}
+	te.candles = candles.NewAggregator(500, te.onCandleUpdate)
+	return te
+}
+
+// onCandleUpdate is the candles.Aggregator hook that fans each updated
+// or newly-closed bar out to kline.<period>@<symbol> subscribers.
+func (te *TradingEngine) onCandleUpdate(c candles.Candle) {
+	te.publish("kline."+c.Period+"@"+c.Symbol, MarketData{
+		Type:      "kline." + c.Period,
+		Symbol:    c.Symbol,
+		Data:      c,
+		Timestamp: time.Now(),
+	})
+}
+
+// publish fans a message out through the Redis-backed hub if this
+// replica has one set up, falling back to local-only delivery
+// otherwise (e.g. Redis was unreachable at startup).
+func (te *TradingEngine) publish(topic string, v interface{}) {
+	if te.redisHub != nil {
+		te.redisHub.Publish(topic, v)
+		return
+	}
+	te.hub.Publish(topic, v)
 }
 
 func (h *Hub) run() {
@@ -175,18 +261,33 @@ func (h *Hub) run() {
 			log.Printf("Client disconnected: %d", client.userID)
 
 		case message := <-h.broadcast:
+			symbol, scoped := topicSymbol(message.topic)
 			h.mu.RLock()
+			var dead []*Client
 			for client := range h.clients {
+				if scoped && !client.symbols[symbol] {
+					continue
+				}
 				select {
-				case client.send <- message:
+				case client.send <- message.data:
 				default:
-					delete(h.clients, client)
-					close(client.send)
-					wsConnections.WithLabelValues("active").Dec()
+					dead = append(dead, client)
 				}
 			}
 			h.mu.RUnlock()
-			messagesStreamed.WithLabelValues("broadcast").Inc()
+
+			if len(dead) > 0 {
+				h.mu.Lock()
+				for _, client := range dead {
+					if _, ok := h.clients[client]; ok {
+						delete(h.clients, client)
+						close(client.send)
+						wsConnections.WithLabelValues("active").Dec()
+					}
+				}
+				h.mu.Unlock()
+			}
+			messagesStreamed.WithLabelValues(topicKind(message.topic)).Inc()
 		}
 	}
 }
@@ -257,20 +358,28 @@ func (c *Client) readPump() {
 
 		// Handle subscription messages
 		var sub struct {
-			Action string   `json:"action"`
-			Symbols []string `json:"symbols"`
+			Action  string            `json:"action"`
+			Symbols []string          `json:"symbols"`
+			LastSeq map[string]uint64 `json:"last_seq"` // resync only: seq each symbol was last caught up to
 		}
-		
+
 		if err := json.Unmarshal(message, &sub); err == nil {
 			switch sub.Action {
 			case "subscribe":
 				for _, symbol := range sub.Symbols {
 					c.symbols[symbol] = true
+					c.te.sendSnapshot(c, symbol)
 				}
 			case "unsubscribe":
 				for _, symbol := range sub.Symbols {
 					delete(c.symbols, symbol)
 				}
+			case "resync":
+				for _, symbol := range sub.Symbols {
+					if c.symbols[symbol] {
+						c.te.sendResync(c, symbol, sub.LastSeq[symbol])
+					}
+				}
 			}
 		}
 	}
@@ -296,6 +405,7 @@ func (te *TradingEngine) handleWebSocket(c *gin.Context) {
 
 	client := &Client{
 		hub:        te.hub,
+		te:         te,
 		conn:       conn,
 		send:       make(chan []byte, 256),
 		userID:     uint32(userID),
@@ -322,34 +432,358 @@ func (te *TradingEngine) placeOrder(c *gin.Context) {
 	}
 
 	order.Timestamp = time.Now()
-	order.ID = uint64(time.Now().UnixNano()) // Simple ID generation
+	order.ID = te.engine.NextOrderID()
 
-	// Process order (integrate with Rust WASM here)
-	orderJSON, _ := json.Marshal(order)
-	
-	// Store in Redis for persistence
-	te.redis.LPush(context.Background(), "orders:"+order.Symbol, orderJSON)
+	isMarket := order.OrderType == matching.TypeMarket
+	order.Price = te.instruments.RoundPrice(order.Symbol, order.Price)
+	order.Quantity = te.instruments.RoundQuantity(order.Symbol, order.Quantity)
+	if err := te.instruments.Validate(order.Symbol, order.Price, order.Quantity, isMarket); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	// Broadcast to WebSocket clients
-	marketData := MarketData{
-		Type:      "order",
+	riskPrice := order.Price
+	if isMarket {
+		// A market order's Price isn't used for matching (instruments.Validate
+		// skips its tick-size and min-notional checks when isMarket is set),
+		// so the real notional isn't known until the order matches. Estimate it from the last trade so MaxNotionalPerSymbol
+		// still applies; with no trade history there's nothing to estimate
+		// against, so refuse rather than letting it through uncapped.
+		riskPrice = te.engine.LastPrice(order.Symbol)
+		if riskPrice == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "no reference price for market order risk check"})
+			return
+		}
+	}
+
+	notional := riskPrice * order.Quantity
+	checkErr := te.risk.Check(c.Request.Context(), order.UserID, order.Symbol, notional)
+	if checkErr != nil {
+		var rejected *risk.ErrRejected
+		if errors.As(checkErr, &rejected) {
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": rejected.Error(), "reason": rejected.Reason})
+			return
+		}
+		// Redis is unreachable: degrade the same way the rest of this
+		// service does elsewhere and let the order through rather than
+		// blocking trading on a down cache.
+		te.logger.Warn("risk check failed, allowing order through:", checkErr)
+	}
+	// A successful Check already reserved notional and an open-order slot
+	// against the full order size; reserved tracks that so we know to
+	// release it below once the real outcome is known.
+	reserved := checkErr == nil
+
+	trades, resting, err := te.engine.PlaceOrder(matching.Order{
+		ID:        order.ID,
 		Symbol:    order.Symbol,
-		Data:      order,
-		Timestamp: time.Now(),
+		Side:      order.Side,
+		Type:      order.OrderType,
+		Price:     order.Price,
+		Quantity:  order.Quantity,
+		UserID:    order.UserID,
+		Timestamp: order.Timestamp,
+	})
+	if err != nil {
+		if reserved {
+			if err := te.risk.RecordClosed(context.Background(), order.UserID, order.Symbol, notional); err != nil {
+				te.logger.Warn("failed to release risk exposure:", err)
+			}
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	broadcastData, _ := json.Marshal(marketData)
-	te.hub.broadcast <- broadcastData
+	// Store in Redis for persistence
+	orderJSON, _ := json.Marshal(order)
+	te.redis.LPush(context.Background(), "orders:"+order.Symbol, orderJSON)
+
+	for _, t := range trades {
+		te.broadcastTrade(order.Symbol, order.Side, t)
+		// The maker leg's own placeOrder call reserved this exposure via
+		// Check when it started resting; release the filled portion now,
+		// and its open-order slot too if this fill closed it out.
+		if err := te.risk.RecordFilled(context.Background(), t.MakerUserID, order.Symbol, t.Price*t.Quantity, t.MakerClosed); err != nil {
+			te.logger.Warn("failed to release maker risk exposure:", err)
+		}
+		te.settleTrade(order.Symbol, order.UserID, order.Side == matching.SideBuy, t)
+	}
+	if len(trades) > 0 || resting != nil {
+		te.broadcastBookUpdate(order.Symbol)
+	}
+	if reserved {
+		switch {
+		case resting == nil:
+			// Nothing ended up resting (fully filled, or a killed
+			// fok/ioc/market leftover) — release the whole reservation
+			// Check made, notional and open-order slot together, the same
+			// way RecordClosed releases a resting order on explicit
+			// cancellation.
+			if err := te.risk.RecordClosed(context.Background(), order.UserID, order.Symbol, notional); err != nil {
+				te.logger.Warn("failed to release risk exposure:", err)
+			}
+		case resting.Remaining < order.Quantity:
+			// Partially filled: release just the matched portion; the
+			// resting remainder keeps its reservation until it's filled
+			// further (RecordFilled) or cancelled (RecordClosed).
+			filled := (order.Quantity - resting.Remaining) * riskPrice
+			if err := te.risk.RecordFilled(context.Background(), order.UserID, order.Symbol, filled, false); err != nil {
+				te.logger.Warn("failed to release risk exposure:", err)
+			}
+		}
+		// resting.Remaining == order.Quantity: the order rested in full,
+		// exactly matching what Check already reserved.
+	}
 
 	ordersProcessed.WithLabelValues(order.Symbol, order.Side, order.OrderType).Inc()
 
+	status := "accepted"
+	switch {
+	case resting == nil && len(trades) == 0:
+		status = "rejected" // fill-or-kill that couldn't be filled, or no liquidity for ioc/market
+	case resting == nil:
+		status = "filled"
+	case resting.Remaining < order.Quantity:
+		status = "partially_filled"
+	default:
+		status = "resting"
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"order_id": order.ID,
-		"status":   "accepted",
+		"order_id":             order.ID,
+		"status":               status,
+		"trades":               trades,
 		"latency_microseconds": float64(time.Since(start).Nanoseconds()) / 1000,
 	})
 }
 
+// cancelOrder removes a resting order from the book for symbol. The
+// caller must identify themselves via the user_id query param, and the
+// order must belong to them.
+func (te *TradingEngine) cancelOrder(c *gin.Context) {
+	symbol := c.Param("symbol")
+	orderID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid order id"})
+		return
+	}
+	userID, err := strconv.ParseUint(c.Query("user_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid or missing user_id"})
+		return
+	}
+
+	cancelled, err := te.engine.CancelOrder(orderID, symbol, uint32(userID))
+	if err != nil {
+		status := http.StatusNotFound
+		if errors.Is(err, matching.ErrNotOrderOwner) {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := te.risk.RecordClosed(context.Background(), cancelled.UserID, symbol, cancelled.Remaining*cancelled.Price); err != nil {
+		te.logger.Warn("failed to release risk exposure:", err)
+	}
+
+	te.broadcastBookUpdate(symbol)
+	c.JSON(http.StatusOK, gin.H{"order_id": orderID, "status": "cancelled"})
+}
+
+// broadcastTrade publishes a single fill to WebSocket subscribers. takerSide
+// is the taker's side, used to assign the trade's buy/sell order IDs.
+func (te *TradingEngine) broadcastTrade(symbol, takerSide string, t matching.Trade) {
+	trade := Trade{
+		ID:        t.ID,
+		Symbol:    t.Symbol,
+		Price:     t.Price,
+		Quantity:  t.Quantity,
+		Timestamp: t.Timestamp,
+	}
+	if takerSide == matching.SideBuy {
+		trade.BuyOrderID, trade.SellOrderID = t.TakerOrderID, t.MakerOrderID
+	} else {
+		trade.BuyOrderID, trade.SellOrderID = t.MakerOrderID, t.TakerOrderID
+	}
+
+	te.publish("trade@"+symbol, MarketData{
+		Type:      "trade",
+		Symbol:    symbol,
+		Data:      trade,
+		Timestamp: time.Now(),
+	})
+
+	te.candles.OnTrade(symbol, t.Price, t.Quantity, t.Timestamp)
+}
+
+// settleTrade feeds both legs of t into the position tracker so a
+// closed position's realized P&L reaches the risk guard's round-loss
+// total and consecutive-loss streak. takerIsBuy is the taker's side;
+// the maker leg is always the other side of the same trade.
+func (te *TradingEngine) settleTrade(symbol string, takerUserID uint32, takerIsBuy bool, t matching.Trade) {
+	te.recordFillOutcome(takerUserID, symbol, takerIsBuy, t.Price, t.Quantity)
+	te.recordFillOutcome(t.MakerUserID, symbol, !takerIsBuy, t.Price, t.Quantity)
+}
+
+// recordFillOutcome applies a single fill to userID's position and, if
+// it closed out some or all of an existing position, folds the
+// realized P&L into the risk guard's loss/win bookkeeping.
+func (te *TradingEngine) recordFillOutcome(userID uint32, symbol string, isBuy bool, price, qty float64) {
+	realized, closed := te.positions.OnFill(userID, symbol, isBuy, price, qty)
+	if !closed {
+		return
+	}
+
+	var err error
+	if realized < 0 {
+		err = te.risk.RecordLoss(context.Background(), userID, -realized)
+	} else {
+		err = te.risk.RecordWin(context.Background(), userID)
+	}
+	if err != nil {
+		te.logger.Warn("failed to record trade outcome:", err)
+	}
+}
+
+// toDepthLevels adapts the matching engine's aggregated levels to the
+// depth package's own Level type, keeping the two packages decoupled.
+func toDepthLevels(levels []matching.Level) []depth.Level {
+	out := make([]depth.Level, len(levels))
+	for i, l := range levels {
+		out[i] = depth.Level{Price: l.Price, Quantity: l.Quantity}
+	}
+	return out
+}
+
+// broadcastBookUpdate pushes the book's current state into the depth
+// buffer as an incremental diff and fans it out on book.update@<symbol>
+// to subscribers of that symbol.
+func (te *TradingEngine) broadcastBookUpdate(symbol string) {
+	bidLevels, askLevels := te.engine.Snapshot(symbol)
+	seq := te.engine.NextSeq(symbol)
+	update := te.depth.Get(symbol).PushUpdate(toDepthLevels(bidLevels), toDepthLevels(askLevels), seq)
+
+	te.publish("book.update@"+symbol, MarketData{
+		Type:      "book.update",
+		Symbol:    symbol,
+		Data:      update,
+		Timestamp: time.Now(),
+	})
+
+	te.cacheOrderBook(te.buildOrderBook(symbol, seq))
+}
+
+// cacheOrderBook stores the current order book in Redis so that every
+// replica's getOrderBook sees the same state, not just whichever
+// replica's in-memory matching engine last handled an order for symbol.
+func (te *TradingEngine) cacheOrderBook(ob OrderBook) {
+	data, err := json.Marshal(ob)
+	if err != nil {
+		te.logger.Error("failed to marshal order book for caching:", err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := te.redis.Set(ctx, "orderbook:"+ob.Symbol, data, 10*time.Second).Err(); err != nil {
+		te.logger.Warn("failed to cache order book in redis:", err)
+	}
+}
+
+// sendSnapshot delivers a one-off full L2 snapshot directly to c,
+// seeding the symbol's depth buffer from the matching engine if this is
+// the first time the symbol has been subscribed to.
+func (te *TradingEngine) sendSnapshot(c *Client, symbol string) {
+	buf := te.depth.Get(symbol)
+	snap := buf.Snapshot()
+	if snap.Seq == 0 {
+		bidLevels, askLevels := te.engine.Snapshot(symbol)
+		snap = buf.SetSnapshot(toDepthLevels(bidLevels), toDepthLevels(askLevels), te.engine.CurrentSeq(symbol))
+	}
+	te.deliver(c, "book.snapshot", symbol, snap)
+}
+
+// sendResync delivers whatever changed on symbol since lastSeq: the
+// buffered incremental diffs if lastSeq is still reachable in the
+// depth buffer's ring, or a full snapshot if the client has fallen too
+// far behind to replay (or never had a seq to begin with).
+func (te *TradingEngine) sendResync(c *Client, symbol string, lastSeq uint64) {
+	if lastSeq == 0 {
+		te.sendSnapshot(c, symbol)
+		return
+	}
+	updates, ok := te.depth.Get(symbol).Since(lastSeq)
+	if !ok {
+		te.sendSnapshot(c, symbol)
+		return
+	}
+	for _, u := range updates {
+		te.deliver(c, "book.update", symbol, u)
+	}
+}
+
+// deliver marshals a market-data envelope of kind carrying data for
+// symbol and queues it for c, dropping it if c's send buffer is full
+// rather than blocking the read loop on a slow client.
+func (te *TradingEngine) deliver(c *Client, kind, symbol string, data interface{}) {
+	marketData := MarketData{
+		Type:      kind,
+		Symbol:    symbol,
+		Data:      data,
+		Timestamp: time.Now(),
+	}
+	payload, err := json.Marshal(marketData)
+	if err != nil {
+		te.logger.Errorf("failed to marshal %s for %s: %v", kind, symbol, err)
+		return
+	}
+
+	select {
+	case c.send <- payload:
+	default:
+		te.logger.Warnf("client %d send buffer full, dropping %s for %s", c.userID, kind, symbol)
+	}
+}
+
+func (te *TradingEngine) buildOrderBook(symbol string, seqNo uint64) OrderBook {
+	bidLevels, askLevels := te.engine.Snapshot(symbol)
+
+	bids := make([][]float64, 0, len(bidLevels))
+	for _, l := range bidLevels {
+		bids = append(bids, []float64{l.Price, l.Quantity})
+	}
+	asks := make([][]float64, 0, len(askLevels))
+	for _, l := range askLevels {
+		asks = append(asks, []float64{l.Price, l.Quantity})
+	}
+
+	lastPrice := te.engine.LastPrice(symbol)
+
+	var spread float64
+	if len(bids) > 0 && len(asks) > 0 {
+		spread = asks[0][0] - bids[0][0]
+	}
+
+	var totalVolume uint64
+	for _, l := range bidLevels {
+		totalVolume += uint64(l.Quantity)
+	}
+	for _, l := range askLevels {
+		totalVolume += uint64(l.Quantity)
+	}
+
+	return OrderBook{
+		Symbol:      symbol,
+		Bids:        bids,
+		Asks:        asks,
+		LastPrice:   lastPrice,
+		Spread:      spread,
+		TotalVolume: totalVolume,
+		SeqNo:       seqNo,
+		Timestamp:   time.Now(),
+	}
+}
+
 func (te *TradingEngine) getOrderBook(c *gin.Context) {
 	start := time.Now()
 	defer func() {
@@ -357,19 +791,29 @@ func (te *TradingEngine) getOrderBook(c *gin.Context) {
 	}()
 
 	symbol := c.Param("symbol")
-	
-	// Mock orderbook data (integrate with Rust WASM)
-	orderbook := OrderBook{
-		Symbol:      symbol,
-		Bids:        [][]float64{{50000.0, 1.5}, {49999.0, 2.1}, {49998.0, 0.8}},
-		Asks:        [][]float64{{50001.0, 1.2}, {50002.0, 2.3}, {50003.0, 1.7}},
-		LastPrice:   50000.0,
-		Spread:      1.0,
-		TotalVolume: 150000,
-		Timestamp:   time.Now(),
+	ob, err := te.bookCache.Get(symbol, func() (OrderBook, error) {
+		return te.fetchOrderBook(c.Request.Context(), symbol)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
 	}
+	c.JSON(http.StatusOK, ob)
+}
 
-	c.JSON(http.StatusOK, orderbook)
+// fetchOrderBook is the bookCache's read-through supplier: it prefers
+// the cross-replica snapshot cached in Redis by cacheOrderBook, falling
+// back to this replica's own in-memory matching engine state if Redis
+// has nothing for symbol yet (e.g. no order has ever touched it).
+func (te *TradingEngine) fetchOrderBook(ctx context.Context, symbol string) (OrderBook, error) {
+	raw, err := te.redis.Get(ctx, "orderbook:"+symbol).Result()
+	if err == nil {
+		var ob OrderBook
+		if jsonErr := json.Unmarshal([]byte(raw), &ob); jsonErr == nil {
+			return ob, nil
+		}
+	}
+	return te.buildOrderBook(symbol, te.engine.CurrentSeq(symbol)), nil
 }
 
 func (te *TradingEngine) getStats(c *gin.Context) {
@@ -452,6 +896,43 @@ func (te *TradingEngine) healthCheck(c *gin.Context) {
 	c.JSON(http.StatusOK, health)
 }
 
+// listInstruments returns every instrument the engine currently
+// validates orders against.
+func (te *TradingEngine) listInstruments(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"instruments": te.instruments.List()})
+}
+
+// getInstrument returns the tick-size/lot-size/min-notional metadata
+// for a single symbol.
+func (te *TradingEngine) getInstrument(c *gin.Context) {
+	symbol := c.Param("symbol")
+	info, ok := te.instruments.Get(symbol)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "unknown symbol"})
+		return
+	}
+	c.JSON(http.StatusOK, info)
+}
+
+// adminHalt flips the cluster-wide risk kill switch. Body: {"halted": bool}.
+// While halted, placeOrder rejects every order with 429 "halted" on every
+// replica, since the switch lives in Redis.
+func (te *TradingEngine) adminHalt(c *gin.Context) {
+	var req struct {
+		Halted bool `json:"halted"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := te.risk.Halt(c.Request.Context(), req.Halted); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"halted": req.Halted})
+}
+
 func setupRoutes(te *TradingEngine) *gin.Engine {
 	gin.SetMode(gin.ReleaseMode)
 	r := gin.New()
@@ -469,10 +950,15 @@ func setupRoutes(te *TradingEngine) *gin.Engine {
 	api := r.Group("/api/v1")
 	{
 		api.POST("/orders", te.placeOrder)
+		api.DELETE("/orders/:symbol/:id", te.cancelOrder)
 		api.GET("/orderbook/:symbol", te.getOrderBook)
+		api.GET("/instruments", te.listInstruments)
+		api.GET("/instruments/:symbol", te.getInstrument)
+		api.GET("/klines/:symbol", te.getKlines)
 		api.GET("/stats", te.getStats)
 		api.GET("/benchmark", te.benchmarkPerformance)
 		api.GET("/health", te.healthCheck)
+		api.POST("/admin/halt", te.adminHalt)
 	}
 
 	// WebSocket endpoint
@@ -504,10 +990,31 @@ func main() {
 		te.logger.Warn("Redis not available, continuing without persistence:", err)
 	} else {
 		te.logger.Info("âœ… Connected to Redis")
+		if err := te.instruments.LoadFromRedis(ctx, te.redis); err != nil {
+			te.logger.Warn("Failed to load instruments from Redis, using defaults:", err)
+		}
+
+		// Wrap the hub so broadcasts fan out across every replica behind
+		// the load balancer, not just to this process's own clients.
+		te.redisHub = NewRedisHub(te.hub, te.redis)
+		go te.redisHub.Run(context.Background())
 	}
 
 	// Start WebSocket hub
 	go te.hub.run()
+	te.startAdapters(context.Background())
+
+	// Periodically flush closed candles to Redis rather than on every
+	// trade, so persistence stays a cheap background batch.
+	go func() {
+		ticker := time.NewTicker(5 * time.Second)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := te.candles.FlushPending(context.Background(), te.redis); err != nil {
+				te.logger.Warn("failed to flush candles to redis:", err)
+			}
+		}
+	}()
 
 	router := setupRoutes(te)
 	
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"strings"
+
+	"trading-platform/go-streaming-api/adapters"
+)
+
+// startAdapters connects every configured upstream market data source
+// and relays its normalized events into the Hub, so external exchange
+// data shows up on the same WebSocket surface as this service's own
+// matching engine activity.
+func (te *TradingEngine) startAdapters(ctx context.Context) {
+	symbols := strings.Split(getEnv("ADAPTER_SYMBOLS", "BTCUSD,ETHUSD"), ",")
+
+	var sources []adapters.MarketDataSource
+	if getEnv("BINANCE_ADAPTER_ENABLED", "false") == "true" {
+		sources = append(sources, adapters.NewBinanceAdapter())
+	}
+	if key := getEnv("ALPACA_API_KEY", ""); key != "" {
+		feed := getEnv("ALPACA_FEED", "iex")
+		secret := getEnv("ALPACA_API_SECRET", "")
+		sources = append(sources, adapters.NewAlpacaAdapter(feed, key, secret))
+	}
+
+	for _, source := range sources {
+		if err := source.Connect(ctx); err != nil {
+			te.logger.Warn("market data adapter failed to connect:", err)
+			continue
+		}
+		if err := source.Subscribe(symbols); err != nil {
+			te.logger.Warn("market data adapter failed to subscribe:", err)
+		}
+		go te.relayAdapterEvents(source)
+	}
+}
+
+// relayAdapterEvents republishes everything an adapter emits onto the
+// Hub under a per-symbol, per-kind topic (e.g. "adapter.trade@BTCUSD").
+func (te *TradingEngine) relayAdapterEvents(source adapters.MarketDataSource) {
+	for event := range source.Events() {
+		te.publish("adapter."+event.Type+"@"+event.Symbol, MarketData{
+			Type:      "adapter." + event.Type,
+			Symbol:    event.Symbol,
+			Data:      event,
+			Timestamp: event.Timestamp,
+		})
+	}
+}
@@ -0,0 +1,416 @@
+// Package matching implements an in-memory, price-time priority limit
+// order book and matching engine, one per symbol.
+package matching
+
+import (
+	"container/list"
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Order types supported by the engine.
+const (
+	TypeLimit  = "limit"
+	TypeMarket = "market"
+	TypeIOC    = "ioc" // immediate-or-cancel
+	TypeFOK    = "fok" // fill-or-kill
+)
+
+// Sides.
+const (
+	SideBuy  = "buy"
+	SideSell = "sell"
+)
+
+var (
+	// ErrUnknownOrderType is returned by PlaceOrder for an unrecognized OrderType.
+	ErrUnknownOrderType = errors.New("matching: unknown order type")
+	// ErrInvalidSide is returned by PlaceOrder for an unrecognized Side.
+	ErrInvalidSide = errors.New("matching: invalid side")
+	// ErrOrderNotFound is returned by CancelOrder when the order is no longer resting.
+	ErrOrderNotFound = errors.New("matching: order not found")
+	// ErrNotOrderOwner is returned by CancelOrder when userID doesn't match
+	// the resting order's owner.
+	ErrNotOrderOwner = errors.New("matching: order belongs to a different user")
+)
+
+// Order is a single resting or incoming order known to the engine.
+type Order struct {
+	ID        uint64
+	Symbol    string
+	Side      string
+	Type      string
+	Price     float64
+	Quantity  float64
+	Remaining float64
+	UserID    uint32
+	Timestamp time.Time
+
+	// seq preserves FIFO ordering of orders resting at the same price.
+	seq uint64
+	// elem is the order's position within its price level's queue, kept so
+	// CancelOrder can remove it in O(1) once the level has been located.
+	elem *list.Element
+}
+
+// Trade records a single match between a resting maker order and an
+// incoming taker order.
+type Trade struct {
+	ID           uint64
+	Symbol       string
+	MakerOrderID uint64
+	MakerUserID  uint32
+	TakerOrderID uint64
+	Price        float64
+	Quantity     float64
+	Timestamp    time.Time
+	// MakerClosed reports whether this trade fully filled the maker leg,
+	// removing it from the book. Callers that booked exposure against
+	// the maker order while it rested use this to know when to release
+	// the order's open-order slot, not just its filled notional.
+	MakerClosed bool
+}
+
+// PriceLevel holds the FIFO queue of orders resting at a single price.
+type PriceLevel struct {
+	Price  float64
+	orders *list.List // of *Order
+}
+
+func newPriceLevel(price float64) *PriceLevel {
+	return &PriceLevel{Price: price, orders: list.New()}
+}
+
+// Quantity returns the total resting quantity at this level.
+func (pl *PriceLevel) Quantity() float64 {
+	var total float64
+	for e := pl.orders.Front(); e != nil; e = e.Next() {
+		total += e.Value.(*Order).Remaining
+	}
+	return total
+}
+
+// book is the per-symbol order book: bids sorted highest-first, asks
+// sorted lowest-first. Levels are kept in a sorted slice with binary
+// search insertion, which gives us the ordering properties of a
+// red-black tree / skiplist without pulling in a third-party dependency.
+type book struct {
+	symbol    string
+	bids      []*PriceLevel // descending by price
+	asks      []*PriceLevel // ascending by price
+	orders    map[uint64]*Order
+	seq       uint64  // FIFO counter for orders resting at the same price
+	msgSeq    uint64  // outbound message sequence number, for gap detection
+	lastPrice float64 // price of the most recent trade, 0 if none yet
+}
+
+func newBook(symbol string) *book {
+	return &book{
+		symbol: symbol,
+		orders: make(map[uint64]*Order),
+	}
+}
+
+func (b *book) levels(side string) []*PriceLevel {
+	if side == SideBuy {
+		return b.bids
+	}
+	return b.asks
+}
+
+func (b *book) setLevels(side string, levels []*PriceLevel) {
+	if side == SideBuy {
+		b.bids = levels
+	} else {
+		b.asks = levels
+	}
+}
+
+// findLevel returns the level at price on side, creating and inserting
+// it in sorted order if it doesn't already exist.
+func (b *book) findLevel(side string, price float64) *PriceLevel {
+	levels := b.levels(side)
+	var idx int
+	if side == SideBuy {
+		idx = sort.Search(len(levels), func(i int) bool { return levels[i].Price <= price })
+	} else {
+		idx = sort.Search(len(levels), func(i int) bool { return levels[i].Price >= price })
+	}
+	if idx < len(levels) && levels[idx].Price == price {
+		return levels[idx]
+	}
+	pl := newPriceLevel(price)
+	levels = append(levels, nil)
+	copy(levels[idx+1:], levels[idx:])
+	levels[idx] = pl
+	b.setLevels(side, levels)
+	return pl
+}
+
+// removeLevelIfEmpty drops a price level once its queue drains to zero.
+func (b *book) removeLevelIfEmpty(side string, pl *PriceLevel) {
+	if pl.orders.Len() > 0 {
+		return
+	}
+	levels := b.levels(side)
+	for i, l := range levels {
+		if l == pl {
+			b.setLevels(side, append(levels[:i], levels[i+1:]...))
+			return
+		}
+	}
+}
+
+func oppositeSide(side string) string {
+	if side == SideBuy {
+		return SideSell
+	}
+	return SideBuy
+}
+
+// crosses reports whether a resting level on the opposing side is
+// marketable against the incoming order.
+func crosses(side string, incomingPrice, levelPrice float64, marketable bool) bool {
+	if marketable {
+		return true
+	}
+	if side == SideBuy {
+		return incomingPrice >= levelPrice
+	}
+	return incomingPrice <= levelPrice
+}
+
+// Engine is a collection of per-symbol order books.
+type Engine struct {
+	mu       sync.Mutex
+	books    map[string]*book
+	orderID  uint64
+	tradeID  uint64
+}
+
+// NewEngine creates an empty matching engine.
+func NewEngine() *Engine {
+	return &Engine{books: make(map[string]*book)}
+}
+
+func (e *Engine) bookFor(symbol string) *book {
+	b, ok := e.books[symbol]
+	if !ok {
+		b = newBook(symbol)
+		e.books[symbol] = b
+	}
+	return b
+}
+
+// NextOrderID allocates a process-unique order ID.
+func (e *Engine) NextOrderID() uint64 {
+	return atomic.AddUint64(&e.orderID, 1)
+}
+
+// PlaceOrder submits an order for matching. It returns any trades
+// generated and the resting remainder, if any (nil if fully filled or
+// the order type does not rest, e.g. market/ioc/fok).
+func (e *Engine) PlaceOrder(o Order) (trades []Trade, resting *Order, err error) {
+	if o.Side != SideBuy && o.Side != SideSell {
+		return nil, nil, ErrInvalidSide
+	}
+	switch o.Type {
+	case TypeLimit, TypeMarket, TypeIOC, TypeFOK:
+	default:
+		return nil, nil, ErrUnknownOrderType
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	b := e.bookFor(o.Symbol)
+	o.Remaining = o.Quantity
+	opposite := oppositeSide(o.Side)
+
+	if o.Type == TypeFOK && !e.fillable(b, opposite, o) {
+		return nil, nil, nil
+	}
+
+	trades = e.match(b, opposite, &o)
+
+	if o.Remaining > 0 && o.Type == TypeLimit {
+		o.seq = b.seq
+		b.seq++
+		pl := b.findLevel(o.Side, o.Price)
+		stored := o
+		elem := pl.orders.PushBack(&stored)
+		stored.elem = elem
+		b.orders[o.ID] = &stored
+
+		// Return a snapshot copy, not a pointer into the book: stored
+		// keeps getting mutated by later PlaceOrder calls that match
+		// against it, and the caller reads resting.Remaining after the
+		// engine mutex is released.
+		restingCopy := stored
+		restingCopy.elem = nil
+		resting = &restingCopy
+	}
+
+	return trades, resting, nil
+}
+
+// fillable reports whether the book currently holds enough marketable
+// liquidity to fill o completely — used to implement fill-or-kill.
+func (e *Engine) fillable(b *book, opposite string, o Order) bool {
+	remaining := o.Quantity
+	for _, pl := range b.levels(opposite) {
+		if !crosses(o.Side, o.Price, pl.Price, o.Type == TypeMarket) {
+			break
+		}
+		remaining -= pl.Quantity()
+		if remaining <= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// match walks the opposing side of the book crossing prices until the
+// incoming order is filled or no further marketable liquidity remains.
+func (e *Engine) match(b *book, opposite string, o *Order) []Trade {
+	var trades []Trade
+	marketable := o.Type == TypeMarket
+
+	for o.Remaining > 0 {
+		levels := b.levels(opposite)
+		if len(levels) == 0 {
+			break
+		}
+		pl := levels[0]
+		if !crosses(o.Side, o.Price, pl.Price, marketable) {
+			break
+		}
+
+		for o.Remaining > 0 && pl.orders.Len() > 0 {
+			front := pl.orders.Front()
+			maker := front.Value.(*Order)
+
+			qty := maker.Remaining
+			if o.Remaining < qty {
+				qty = o.Remaining
+			}
+
+			maker.Remaining -= qty
+			o.Remaining -= qty
+			closed := maker.Remaining <= 0
+
+			e.tradeID++
+			trades = append(trades, Trade{
+				ID:           e.tradeID,
+				Symbol:       b.symbol,
+				MakerOrderID: maker.ID,
+				MakerUserID:  maker.UserID,
+				TakerOrderID: o.ID,
+				Price:        pl.Price,
+				Quantity:     qty,
+				Timestamp:    time.Now(),
+				MakerClosed:  closed,
+			})
+
+			b.lastPrice = pl.Price
+
+			if closed {
+				pl.orders.Remove(front)
+				delete(b.orders, maker.ID)
+			}
+		}
+
+		b.removeLevelIfEmpty(opposite, pl)
+	}
+
+	return trades
+}
+
+// CancelOrder removes a resting order from symbol's book and returns a
+// copy of it as it stood at cancellation, so callers can release any
+// exposure they booked against it (e.g. risk limits). userID must match
+// the resting order's owner, or ErrNotOrderOwner is returned and the
+// order is left untouched.
+func (e *Engine) CancelOrder(orderID uint64, symbol string, userID uint32) (Order, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	b, ok := e.books[symbol]
+	if !ok {
+		return Order{}, ErrOrderNotFound
+	}
+	o, ok := b.orders[orderID]
+	if !ok {
+		return Order{}, ErrOrderNotFound
+	}
+	if o.UserID != userID {
+		return Order{}, ErrNotOrderOwner
+	}
+	pl := b.findLevel(o.Side, o.Price)
+	pl.orders.Remove(o.elem)
+	delete(b.orders, orderID)
+	b.removeLevelIfEmpty(o.Side, pl)
+
+	cancelled := *o
+	cancelled.elem = nil
+	return cancelled, nil
+}
+
+// Level is an aggregated price/quantity pair used for snapshots.
+type Level struct {
+	Price    float64
+	Quantity float64
+}
+
+// Snapshot returns the current aggregated book for symbol, bids first
+// (best bid first) then asks (best ask first).
+func (e *Engine) Snapshot(symbol string) (bids, asks []Level) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	b, ok := e.books[symbol]
+	if !ok {
+		return nil, nil
+	}
+	for _, pl := range b.bids {
+		bids = append(bids, Level{Price: pl.Price, Quantity: pl.Quantity()})
+	}
+	for _, pl := range b.asks {
+		asks = append(asks, Level{Price: pl.Price, Quantity: pl.Quantity()})
+	}
+	return bids, asks
+}
+
+// LastPrice returns the price of the most recent trade on symbol, or 0
+// if no trade has occurred yet.
+func (e *Engine) LastPrice(symbol string) float64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	b, ok := e.books[symbol]
+	if !ok {
+		return 0
+	}
+	return b.lastPrice
+}
+
+// NextSeq allocates and returns the next sequence number for symbol, so
+// callers (e.g. the WebSocket layer) can tag outbound messages and let
+// clients detect gaps and resync.
+func (e *Engine) NextSeq(symbol string) uint64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	b := e.bookFor(symbol)
+	b.msgSeq++
+	return b.msgSeq
+}
+
+// CurrentSeq returns the last sequence number issued for symbol without
+// allocating a new one, for plain reads that shouldn't advance the stream.
+func (e *Engine) CurrentSeq(symbol string) uint64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.bookFor(symbol).msgSeq
+}
@@ -0,0 +1,198 @@
+package matching
+
+import "testing"
+
+func TestPlaceOrderCrossesAndFillsPartially(t *testing.T) {
+	e := NewEngine()
+
+	_, resting, err := e.PlaceOrder(Order{ID: 1, Symbol: "BTCUSD", Side: SideSell, Type: TypeLimit, Price: 100, Quantity: 10})
+	if err != nil {
+		t.Fatalf("place resting sell: %v", err)
+	}
+	if resting == nil || resting.Remaining != 10 {
+		t.Fatalf("expected a resting sell of 10, got %+v", resting)
+	}
+
+	trades, taker, err := e.PlaceOrder(Order{ID: 2, Symbol: "BTCUSD", Side: SideBuy, Type: TypeLimit, Price: 100, Quantity: 4})
+	if err != nil {
+		t.Fatalf("place crossing buy: %v", err)
+	}
+	if len(trades) != 1 {
+		t.Fatalf("expected 1 trade, got %d", len(trades))
+	}
+	if trades[0].Quantity != 4 || trades[0].Price != 100 {
+		t.Fatalf("unexpected trade: %+v", trades[0])
+	}
+	if trades[0].MakerOrderID != 1 || trades[0].TakerOrderID != 2 {
+		t.Fatalf("unexpected maker/taker IDs: %+v", trades[0])
+	}
+	if trades[0].MakerClosed {
+		t.Fatalf("maker should not be closed after a partial fill")
+	}
+	if taker != nil {
+		t.Fatalf("4-of-10 buy should not rest at a price the maker already holds")
+	}
+
+	bids, asks := e.Snapshot("BTCUSD")
+	if len(bids) != 0 {
+		t.Fatalf("expected no resting bids, got %+v", bids)
+	}
+	if len(asks) != 1 || asks[0].Quantity != 6 {
+		t.Fatalf("expected 6 remaining on the ask side, got %+v", asks)
+	}
+}
+
+func TestPlaceOrderFullyFillsMaker(t *testing.T) {
+	e := NewEngine()
+
+	e.PlaceOrder(Order{ID: 1, Symbol: "BTCUSD", Side: SideSell, Type: TypeLimit, Price: 100, Quantity: 5})
+	trades, _, err := e.PlaceOrder(Order{ID: 2, Symbol: "BTCUSD", Side: SideBuy, Type: TypeLimit, Price: 100, Quantity: 5})
+	if err != nil {
+		t.Fatalf("place crossing buy: %v", err)
+	}
+	if len(trades) != 1 || !trades[0].MakerClosed {
+		t.Fatalf("expected a single trade that closes the maker, got %+v", trades)
+	}
+
+	if _, err := e.CancelOrder(1, "BTCUSD", 0); err != ErrOrderNotFound {
+		t.Fatalf("expected fully-filled maker to be gone from the book, got err=%v", err)
+	}
+}
+
+func TestPlaceOrderFOKKillsWhenUnfillable(t *testing.T) {
+	e := NewEngine()
+	e.PlaceOrder(Order{ID: 1, Symbol: "BTCUSD", Side: SideSell, Type: TypeLimit, Price: 100, Quantity: 3})
+
+	trades, resting, err := e.PlaceOrder(Order{ID: 2, Symbol: "BTCUSD", Side: SideBuy, Type: TypeFOK, Price: 100, Quantity: 10})
+	if err != nil {
+		t.Fatalf("place fok: %v", err)
+	}
+	if trades != nil || resting != nil {
+		t.Fatalf("expected fok to be killed with no trades and nothing resting, got trades=%+v resting=%+v", trades, resting)
+	}
+
+	bids, asks := e.Snapshot("BTCUSD")
+	if len(bids) != 0 || len(asks) != 1 || asks[0].Quantity != 3 {
+		t.Fatalf("book should be untouched by a killed fok, got bids=%+v asks=%+v", bids, asks)
+	}
+}
+
+func TestPlaceOrderFOKFillsWhenLiquiditySufficient(t *testing.T) {
+	e := NewEngine()
+	e.PlaceOrder(Order{ID: 1, Symbol: "BTCUSD", Side: SideSell, Type: TypeLimit, Price: 100, Quantity: 10})
+
+	trades, resting, err := e.PlaceOrder(Order{ID: 2, Symbol: "BTCUSD", Side: SideBuy, Type: TypeFOK, Price: 100, Quantity: 10})
+	if err != nil {
+		t.Fatalf("place fok: %v", err)
+	}
+	if resting != nil {
+		t.Fatalf("fok never rests, got %+v", resting)
+	}
+	if len(trades) != 1 || trades[0].Quantity != 10 {
+		t.Fatalf("expected a single 10-unit trade, got %+v", trades)
+	}
+}
+
+func TestPlaceOrderMarketWithNoLiquidity(t *testing.T) {
+	e := NewEngine()
+
+	trades, resting, err := e.PlaceOrder(Order{ID: 1, Symbol: "BTCUSD", Side: SideBuy, Type: TypeMarket, Quantity: 5})
+	if err != nil {
+		t.Fatalf("place market: %v", err)
+	}
+	if trades != nil {
+		t.Fatalf("expected no trades against an empty book, got %+v", trades)
+	}
+	if resting != nil {
+		t.Fatalf("market orders never rest, got %+v", resting)
+	}
+}
+
+func TestPlaceOrderIOCRestsNothing(t *testing.T) {
+	e := NewEngine()
+	e.PlaceOrder(Order{ID: 1, Symbol: "BTCUSD", Side: SideSell, Type: TypeLimit, Price: 100, Quantity: 2})
+
+	trades, resting, err := e.PlaceOrder(Order{ID: 2, Symbol: "BTCUSD", Side: SideBuy, Type: TypeIOC, Price: 100, Quantity: 5})
+	if err != nil {
+		t.Fatalf("place ioc: %v", err)
+	}
+	if len(trades) != 1 || trades[0].Quantity != 2 {
+		t.Fatalf("expected ioc to fill the available 2, got %+v", trades)
+	}
+	if resting != nil {
+		t.Fatalf("ioc should never rest its unfilled remainder, got %+v", resting)
+	}
+}
+
+func TestPlaceOrderReturnsSnapshotNotLivePointer(t *testing.T) {
+	e := NewEngine()
+
+	_, resting, err := e.PlaceOrder(Order{ID: 1, Symbol: "BTCUSD", Side: SideSell, Type: TypeLimit, Price: 100, Quantity: 10})
+	if err != nil {
+		t.Fatalf("place resting sell: %v", err)
+	}
+
+	if _, _, err := e.PlaceOrder(Order{ID: 2, Symbol: "BTCUSD", Side: SideBuy, Type: TypeLimit, Price: 100, Quantity: 4}); err != nil {
+		t.Fatalf("place crossing buy: %v", err)
+	}
+
+	if resting.Remaining != 10 {
+		t.Fatalf("resting snapshot should be unaffected by a later match against the live order, got Remaining=%v", resting.Remaining)
+	}
+}
+
+func TestCancelOrderReleasesTheLevel(t *testing.T) {
+	e := NewEngine()
+	e.PlaceOrder(Order{ID: 1, Symbol: "BTCUSD", Side: SideBuy, Type: TypeLimit, Price: 100, Quantity: 1})
+
+	cancelled, err := e.CancelOrder(1, "BTCUSD", 0)
+	if err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	if cancelled.Remaining != 1 {
+		t.Fatalf("expected the cancelled copy to report the remaining quantity, got %+v", cancelled)
+	}
+
+	bids, _ := e.Snapshot("BTCUSD")
+	if len(bids) != 0 {
+		t.Fatalf("expected the now-empty price level to be removed, got %+v", bids)
+	}
+
+	if _, err := e.CancelOrder(1, "BTCUSD", 0); err != ErrOrderNotFound {
+		t.Fatalf("expected ErrOrderNotFound on double-cancel, got %v", err)
+	}
+}
+
+func TestCancelOrderRejectsWrongOwner(t *testing.T) {
+	e := NewEngine()
+	e.PlaceOrder(Order{ID: 1, Symbol: "BTCUSD", Side: SideBuy, Type: TypeLimit, Price: 100, Quantity: 1, UserID: 7})
+
+	if _, err := e.CancelOrder(1, "BTCUSD", 8); err != ErrNotOrderOwner {
+		t.Fatalf("expected ErrNotOrderOwner, got %v", err)
+	}
+
+	bids, _ := e.Snapshot("BTCUSD")
+	if len(bids) != 1 {
+		t.Fatalf("order should still be resting after a rejected cancel, got %+v", bids)
+	}
+
+	if _, err := e.CancelOrder(1, "BTCUSD", 7); err != nil {
+		t.Fatalf("the real owner should still be able to cancel: %v", err)
+	}
+}
+
+func TestPlaceOrderTracksLastPrice(t *testing.T) {
+	e := NewEngine()
+	if got := e.LastPrice("BTCUSD"); got != 0 {
+		t.Fatalf("expected 0 before any trade, got %v", got)
+	}
+
+	e.PlaceOrder(Order{ID: 1, Symbol: "BTCUSD", Side: SideSell, Type: TypeLimit, Price: 100, Quantity: 5})
+	if _, _, err := e.PlaceOrder(Order{ID: 2, Symbol: "BTCUSD", Side: SideBuy, Type: TypeLimit, Price: 100, Quantity: 5}); err != nil {
+		t.Fatalf("place crossing buy: %v", err)
+	}
+
+	if got := e.LastPrice("BTCUSD"); got != 100 {
+		t.Fatalf("expected last price 100, got %v", got)
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisEventChannelPrefix namespaces the Redis pub/sub channels used to
+// fan Hub broadcasts out across replicas. Each symbol gets its own
+// channel so a replica can subscribe narrowly if it ever needs to.
+const redisEventChannelPrefix = "trading.events."
+
+// redisEnvelope is what actually travels over Redis: the original
+// topic plus the publishing node's ID, so a node can recognize and
+// discard its own messages echoed back by the subscription loop.
+type redisEnvelope struct {
+	NodeID string          `json:"node_id"`
+	Topic  string          `json:"topic"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// RedisHub wraps a Hub so that every Publish also fans out over Redis
+// pub/sub, and messages published by other replicas are re-injected
+// into this process's local Hub. This is what lets the WebSocket layer
+// run behind a load balancer with N replicas while every subscriber,
+// regardless of which replica it's connected to, sees every event.
+type RedisHub struct {
+	hub    *Hub
+	redis  *redis.Client
+	nodeID string
+}
+
+// NewRedisHub wraps hub with Redis-backed fanout. nodeID uniquely
+// identifies this process so its own published messages aren't
+// re-delivered to itself when they come back over the subscription.
+func NewRedisHub(hub *Hub, rdb *redis.Client) *RedisHub {
+	return &RedisHub{
+		hub:    hub,
+		redis:  rdb,
+		nodeID: newNodeID(),
+	}
+}
+
+func newNodeID() string {
+	host, _ := os.Hostname()
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
+
+func redisChannelFor(topic string) string {
+	if symbol, scoped := topicSymbol(topic); scoped {
+		return redisEventChannelPrefix + symbol
+	}
+	return redisEventChannelPrefix + "global"
+}
+
+// Publish delivers v to local subscribers immediately, then publishes
+// it to Redis so every other replica's RedisHub can do the same.
+func (rh *RedisHub) Publish(topic string, v interface{}) {
+	rh.hub.Publish(topic, v)
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		log.Printf("redis hub: failed to marshal message for topic %s: %v", topic, err)
+		return
+	}
+	payload, err := json.Marshal(redisEnvelope{NodeID: rh.nodeID, Topic: topic, Data: data})
+	if err != nil {
+		log.Printf("redis hub: failed to marshal envelope for topic %s: %v", topic, err)
+		return
+	}
+	if err := rh.redis.Publish(context.Background(), redisChannelFor(topic), payload).Err(); err != nil {
+		log.Printf("redis hub: publish to %s failed: %v", redisChannelFor(topic), err)
+	}
+}
+
+// Run subscribes to every replica's events and re-injects anything not
+// originated by this node into the local Hub. It blocks until ctx is
+// cancelled and should be started in its own goroutine.
+func (rh *RedisHub) Run(ctx context.Context) {
+	pubsub := rh.redis.PSubscribe(ctx, redisEventChannelPrefix+"*")
+	defer pubsub.Close()
+
+	ch := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			var env redisEnvelope
+			if err := json.Unmarshal([]byte(msg.Payload), &env); err != nil {
+				log.Printf("redis hub: failed to decode message on %s: %v", msg.Channel, err)
+				continue
+			}
+			if env.NodeID == rh.nodeID {
+				continue // our own Publish already delivered this locally
+			}
+			rh.hub.broadcast <- topicMessage{topic: env.Topic, data: env.Data}
+		}
+	}
+}
+
+// bookCacheEntry is a single cached order book, valid until expires.
+type bookCacheEntry struct {
+	book    OrderBook
+	expires time.Time
+}
+
+// bookCache is a small local read-through cache in front of the
+// cross-replica order book state kept in Redis: a cache hit avoids a
+// round trip to Redis on every GET /orderbook/:symbol, at the cost of
+// serving a snapshot that's up to ttl stale.
+type bookCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]bookCacheEntry
+}
+
+func newBookCache(ttl time.Duration) *bookCache {
+	return &bookCache{ttl: ttl, entries: make(map[string]bookCacheEntry)}
+}
+
+// Get returns the cached order book for symbol if it's still fresh,
+// otherwise calls supplier to fetch one and caches the result.
+func (bc *bookCache) Get(symbol string, supplier func() (OrderBook, error)) (OrderBook, error) {
+	bc.mu.Lock()
+	if entry, ok := bc.entries[symbol]; ok && time.Now().Before(entry.expires) {
+		bc.mu.Unlock()
+		return entry.book, nil
+	}
+	bc.mu.Unlock()
+
+	book, err := supplier()
+	if err != nil {
+		return OrderBook{}, err
+	}
+
+	bc.mu.Lock()
+	bc.entries[symbol] = bookCacheEntry{book: book, expires: time.Now().Add(bc.ttl)}
+	bc.mu.Unlock()
+
+	return book, nil
+}
@@ -0,0 +1,252 @@
+// Package risk enforces pre-trade guardrails — per-user open-order caps,
+// per-symbol notional exposure limits, a per-round loss budget, and a
+// consecutive-loss circuit breaker — before an order is allowed to reach
+// the matching engine. All of Guard's state lives in Redis so the limits hold
+// across every replica behind the load balancer, not just the one that
+// happens to handle a given request.
+package risk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Limits configures the guardrails a Guard enforces. A zero value
+// disables the corresponding check.
+type Limits struct {
+	MaxOpenOrdersPerUser int           // open resting orders a single user may have at once
+	MaxNotionalPerSymbol float64       // sum of resting order notional per symbol
+	MaxLossPerRound      float64       // cumulative realized loss before a user is cut off
+	MaxConsecutiveLosses int           // losing trades in a row before the breaker trips
+	BreakerCooldown      time.Duration // how long a tripped breaker blocks new orders
+}
+
+// DefaultLimits returns conservative defaults suitable for a fresh
+// deployment; operators are expected to tune these for their own risk
+// appetite.
+func DefaultLimits() Limits {
+	return Limits{
+		MaxOpenOrdersPerUser: 200,
+		MaxNotionalPerSymbol: 1_000_000,
+		MaxLossPerRound:      50_000,
+		MaxConsecutiveLosses: 10,
+		BreakerCooldown:      5 * time.Minute,
+	}
+}
+
+// Rejection reasons. These double as the trading_risk_rejections_total
+// "reason" label, so keep them stable once deployed.
+const (
+	ReasonHalted         = "halted"
+	ReasonCircuitBreaker = "circuit_breaker"
+	ReasonMaxOpenOrders  = "max_open_orders"
+	ReasonMaxNotional    = "max_notional"
+	ReasonMaxRoundLoss   = "max_round_loss"
+)
+
+// ErrRejected is returned by Check when an order fails a guardrail;
+// callers should inspect Reason to build a structured API response.
+type ErrRejected struct {
+	Reason string
+}
+
+func (e *ErrRejected) Error() string {
+	return fmt.Sprintf("risk: rejected: %s", e.Reason)
+}
+
+// Guard checks proposed orders against Limits using Redis-backed
+// counters.
+type Guard struct {
+	rdb    *redis.Client
+	limits Limits
+}
+
+// NewGuard creates a Guard enforcing limits using rdb for shared state.
+func NewGuard(rdb *redis.Client, limits Limits) *Guard {
+	return &Guard{rdb: rdb, limits: limits}
+}
+
+// reserveScript atomically checks MaxOpenOrdersPerUser and
+// MaxNotionalPerSymbol against the current counters and, only if both
+// clear, books notional against both in the same round trip. A plain
+// GET-then-INCR from Go can't be made atomic across two concurrent
+// requests — both can read the pre-increment value and both pass —
+// but the whole script runs as a single step on the Redis server, so no
+// other command can interleave between the check and the increment.
+var reserveScript = redis.NewScript(`
+local open_key = KEYS[1]
+local notional_key = KEYS[2]
+local max_open = tonumber(ARGV[1])
+local max_notional = tonumber(ARGV[2])
+local notional = tonumber(ARGV[3])
+
+local open = tonumber(redis.call('GET', open_key) or '0')
+if max_open > 0 and open + 1 > max_open then
+	return 'max_open_orders'
+end
+
+local exposure = tonumber(redis.call('GET', notional_key) or '0')
+if max_notional > 0 and exposure + notional > max_notional then
+	return 'max_notional'
+end
+
+redis.call('INCR', open_key)
+redis.call('INCRBYFLOAT', notional_key, notional)
+return 'ok'
+`)
+
+// Check enforces every configured guardrail for a proposed order of the
+// given notional (price*quantity) by userID on symbol, in this order:
+// global kill switch, circuit breaker, round loss, open-order count,
+// notional exposure. It returns an *ErrRejected if any limit is
+// exceeded, and a plain error only on a Redis failure.
+//
+// A Check that clears the open-order count and notional exposure limits
+// reserves against both atomically in the same call — callers must
+// release the reservation once the real outcome is known (the order
+// didn't rest at all, rested in full, or only partially filled) via
+// RecordClosed/RecordFilled, the same way they already release exposure
+// booked by a resting order.
+func (g *Guard) Check(ctx context.Context, userID uint32, symbol string, notional float64) error {
+	halted, err := g.Halted(ctx)
+	if err != nil {
+		return fmt.Errorf("risk: check halt: %w", err)
+	}
+	if halted {
+		rejectionsTotal.WithLabelValues(ReasonHalted).Inc()
+		return &ErrRejected{Reason: ReasonHalted}
+	}
+
+	tripped, err := g.rdb.Exists(ctx, breakerKey(userID)).Result()
+	if err != nil {
+		return fmt.Errorf("risk: check breaker: %w", err)
+	}
+	if tripped > 0 {
+		rejectionsTotal.WithLabelValues(ReasonCircuitBreaker).Inc()
+		return &ErrRejected{Reason: ReasonCircuitBreaker}
+	}
+
+	if g.limits.MaxLossPerRound > 0 {
+		loss, err := getFloat(ctx, g.rdb, lossKey(userID))
+		if err != nil {
+			return fmt.Errorf("risk: check round loss: %w", err)
+		}
+		if loss >= g.limits.MaxLossPerRound {
+			rejectionsTotal.WithLabelValues(ReasonMaxRoundLoss).Inc()
+			return &ErrRejected{Reason: ReasonMaxRoundLoss}
+		}
+	}
+
+	reason, err := reserveScript.Run(ctx, g.rdb, []string{openOrdersKey(userID), notionalKey(symbol)},
+		g.limits.MaxOpenOrdersPerUser, g.limits.MaxNotionalPerSymbol, notional).Text()
+	if err != nil {
+		return fmt.Errorf("risk: reserve open orders/notional: %w", err)
+	}
+	if reason != "ok" {
+		rejectionsTotal.WithLabelValues(reason).Inc()
+		return &ErrRejected{Reason: reason}
+	}
+
+	return nil
+}
+
+// RecordClosed releases the exposure booked by Check once an order
+// leaves in one step, e.g. it is cancelled or never rested (the given
+// notional and the open-order slot are released together).
+func (g *Guard) RecordClosed(ctx context.Context, userID uint32, symbol string, notional float64) error {
+	pipe := g.rdb.Pipeline()
+	pipe.Decr(ctx, openOrdersKey(userID))
+	pipe.IncrByFloat(ctx, notionalKey(symbol), -notional)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// RecordFilled releases the notional a maker order consumed by being
+// matched as someone else's fill. Unlike RecordClosed, it only
+// releases the order's open-order slot when closed reports that the
+// fill fully filled the maker leg and removed it from the book —
+// a partial fill keeps resting, so its open-order slot stays booked.
+func (g *Guard) RecordFilled(ctx context.Context, userID uint32, symbol string, notional float64, closed bool) error {
+	pipe := g.rdb.Pipeline()
+	pipe.IncrByFloat(ctx, notionalKey(symbol), -notional)
+	if closed {
+		pipe.Decr(ctx, openOrdersKey(userID))
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// RecordLoss folds a realized loss for userID into their round loss
+// total and consecutive-loss streak, tripping the circuit breaker for
+// BreakerCooldown once MaxConsecutiveLosses is reached. Callers should
+// call RecordWin instead when a trade closed flat or in the user's
+// favor, to reset the streak. PositionTracker.OnFill is the intended
+// source of the realized amount: feed it every fill and call RecordLoss
+// or RecordWin whenever it reports a position was closed.
+func (g *Guard) RecordLoss(ctx context.Context, userID uint32, amount float64) error {
+	if g.limits.MaxLossPerRound > 0 {
+		if err := g.rdb.IncrByFloat(ctx, lossKey(userID), amount).Err(); err != nil {
+			return fmt.Errorf("risk: record round loss: %w", err)
+		}
+	}
+
+	if g.limits.MaxConsecutiveLosses == 0 {
+		return nil
+	}
+	streak, err := g.rdb.Incr(ctx, streakKey(userID)).Result()
+	if err != nil {
+		return fmt.Errorf("risk: record consecutive loss: %w", err)
+	}
+	if streak >= int64(g.limits.MaxConsecutiveLosses) {
+		if err := g.rdb.Set(ctx, breakerKey(userID), 1, g.limits.BreakerCooldown).Err(); err != nil {
+			return fmt.Errorf("risk: trip circuit breaker: %w", err)
+		}
+	}
+	return nil
+}
+
+// RecordWin resets userID's consecutive-loss streak.
+func (g *Guard) RecordWin(ctx context.Context, userID uint32) error {
+	return g.rdb.Set(ctx, streakKey(userID), 0, 0).Err()
+}
+
+// Halt sets or clears the global kill switch. While halted, Check
+// rejects every order regardless of any other limit.
+func (g *Guard) Halt(ctx context.Context, halted bool) error {
+	if !halted {
+		return g.rdb.Del(ctx, haltKey()).Err()
+	}
+	return g.rdb.Set(ctx, haltKey(), "1", 0).Err()
+}
+
+// Halted reports whether the kill switch is currently set.
+func (g *Guard) Halted(ctx context.Context) (bool, error) {
+	err := g.rdb.Get(ctx, haltKey()).Err()
+	switch {
+	case errors.Is(err, redis.Nil):
+		return false, nil
+	case err != nil:
+		return false, err
+	default:
+		return true, nil
+	}
+}
+
+func getFloat(ctx context.Context, rdb *redis.Client, key string) (float64, error) {
+	v, err := rdb.Get(ctx, key).Float64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return v, err
+}
+
+func haltKey() string                    { return "risk:halt" }
+func breakerKey(userID uint32) string    { return fmt.Sprintf("risk:breaker:%d", userID) }
+func openOrdersKey(userID uint32) string { return fmt.Sprintf("risk:open_orders:%d", userID) }
+func notionalKey(symbol string) string   { return fmt.Sprintf("risk:notional:%s", symbol) }
+func lossKey(userID uint32) string       { return fmt.Sprintf("risk:loss:%d", userID) }
+func streakKey(userID uint32) string     { return fmt.Sprintf("risk:loss_streak:%d", userID) }
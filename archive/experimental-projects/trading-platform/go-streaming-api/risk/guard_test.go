@@ -0,0 +1,201 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestGuard(t *testing.T, limits Limits) *Guard {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+	return NewGuard(rdb, limits)
+}
+
+func rejectReason(t *testing.T, err error) string {
+	t.Helper()
+	var rejected *ErrRejected
+	if !errors.As(err, &rejected) {
+		t.Fatalf("expected *ErrRejected, got %v (%T)", err, err)
+	}
+	return rejected.Reason
+}
+
+func TestCheckAllowsWithinLimits(t *testing.T) {
+	g := newTestGuard(t, DefaultLimits())
+	if err := g.Check(context.Background(), 1, "BTCUSD", 1000); err != nil {
+		t.Fatalf("expected no rejection, got %v", err)
+	}
+}
+
+func TestCheckRejectsMaxOpenOrders(t *testing.T) {
+	g := newTestGuard(t, Limits{MaxOpenOrdersPerUser: 1})
+	ctx := context.Background()
+
+	// A passing Check reserves an open-order slot for the order it just
+	// cleared, so this books user 1's one allowed slot.
+	if err := g.Check(ctx, 1, "BTCUSD", 100); err != nil {
+		t.Fatalf("expected the first order to reserve cleanly, got %v", err)
+	}
+
+	err := g.Check(ctx, 1, "BTCUSD", 100)
+	if reason := rejectReason(t, err); reason != ReasonMaxOpenOrders {
+		t.Fatalf("expected %s, got %s", ReasonMaxOpenOrders, reason)
+	}
+}
+
+func TestCheckRejectsMaxNotional(t *testing.T) {
+	g := newTestGuard(t, Limits{MaxNotionalPerSymbol: 1000})
+	ctx := context.Background()
+
+	// A passing Check reserves notional exposure for the order it just
+	// cleared, so this books 900 of the symbol's 1000 limit.
+	if err := g.Check(ctx, 1, "BTCUSD", 900); err != nil {
+		t.Fatalf("expected the first order to reserve cleanly, got %v", err)
+	}
+
+	err := g.Check(ctx, 1, "BTCUSD", 200)
+	if reason := rejectReason(t, err); reason != ReasonMaxNotional {
+		t.Fatalf("expected %s, got %s", ReasonMaxNotional, reason)
+	}
+}
+
+func TestCheckReservesAtomicallyAgainstBothLimits(t *testing.T) {
+	g := newTestGuard(t, Limits{MaxOpenOrdersPerUser: 1, MaxNotionalPerSymbol: 1000})
+	ctx := context.Background()
+
+	// Within the limit on open orders but over on notional: Check must
+	// reject without reserving the open-order slot either, or a
+	// following order that only trips the open-order limit would wrongly
+	// pass.
+	err := g.Check(ctx, 1, "BTCUSD", 1001)
+	if reason := rejectReason(t, err); reason != ReasonMaxNotional {
+		t.Fatalf("expected %s, got %s", ReasonMaxNotional, reason)
+	}
+	if err := g.Check(ctx, 1, "BTCUSD", 1); err != nil {
+		t.Fatalf("expected the rejected check to have left the open-order slot free, got %v", err)
+	}
+}
+
+func TestRecordClosedReleasesExposure(t *testing.T) {
+	g := newTestGuard(t, Limits{MaxOpenOrdersPerUser: 1, MaxNotionalPerSymbol: 1000})
+	ctx := context.Background()
+
+	if err := g.Check(ctx, 1, "BTCUSD", 900); err != nil {
+		t.Fatalf("expected the order to reserve cleanly, got %v", err)
+	}
+	if err := g.RecordClosed(ctx, 1, "BTCUSD", 900); err != nil {
+		t.Fatalf("record closed: %v", err)
+	}
+
+	if err := g.Check(ctx, 1, "BTCUSD", 900); err != nil {
+		t.Fatalf("expected exposure to be fully released, got %v", err)
+	}
+}
+
+func TestRecordFilledReleasesNotionalButKeepsOpenOrderUntilClosed(t *testing.T) {
+	g := newTestGuard(t, Limits{MaxOpenOrdersPerUser: 1, MaxNotionalPerSymbol: 1000})
+	ctx := context.Background()
+
+	if err := g.Check(ctx, 1, "BTCUSD", 1000); err != nil {
+		t.Fatalf("expected the order to reserve cleanly, got %v", err)
+	}
+
+	// Partial fill: notional exposure drops, but the order is still
+	// resting so the open-order slot stays booked.
+	if err := g.RecordFilled(ctx, 1, "BTCUSD", 400, false); err != nil {
+		t.Fatalf("record filled (partial): %v", err)
+	}
+	err := g.Check(ctx, 2, "BTCUSD", 601)
+	if reason := rejectReason(t, err); reason != ReasonMaxNotional {
+		t.Fatalf("expected remaining 600 notional to still be booked, got %s", reason)
+	}
+
+	if err := g.Check(ctx, 1, "BTCUSD", 1); err == nil {
+		t.Fatal("expected user 1's open-order slot to still be consumed after a partial fill")
+	} else if reason := rejectReason(t, err); reason != ReasonMaxOpenOrders {
+		t.Fatalf("expected %s, got %s", ReasonMaxOpenOrders, reason)
+	}
+
+	// The order then fully fills: both the remaining notional and the
+	// open-order slot release.
+	if err := g.RecordFilled(ctx, 1, "BTCUSD", 600, true); err != nil {
+		t.Fatalf("record filled (closing): %v", err)
+	}
+	if err := g.Check(ctx, 1, "BTCUSD", 1000); err != nil {
+		t.Fatalf("expected exposure fully released after close, got %v", err)
+	}
+}
+
+func TestCheckRejectsWhileHalted(t *testing.T) {
+	g := newTestGuard(t, DefaultLimits())
+	ctx := context.Background()
+
+	if err := g.Halt(ctx, true); err != nil {
+		t.Fatalf("halt: %v", err)
+	}
+
+	err := g.Check(ctx, 1, "BTCUSD", 1)
+	if reason := rejectReason(t, err); reason != ReasonHalted {
+		t.Fatalf("expected %s, got %s", ReasonHalted, reason)
+	}
+}
+
+func TestRecordLossTripsCircuitBreaker(t *testing.T) {
+	g := newTestGuard(t, Limits{MaxConsecutiveLosses: 2, BreakerCooldown: 0})
+	ctx := context.Background()
+
+	if err := g.RecordLoss(ctx, 1, 10); err != nil {
+		t.Fatalf("record loss 1: %v", err)
+	}
+	if err := g.Check(ctx, 1, "BTCUSD", 1); err != nil {
+		t.Fatalf("expected breaker not yet tripped, got %v", err)
+	}
+
+	if err := g.RecordLoss(ctx, 1, 10); err != nil {
+		t.Fatalf("record loss 2: %v", err)
+	}
+
+	err := g.Check(ctx, 1, "BTCUSD", 1)
+	if reason := rejectReason(t, err); reason != ReasonCircuitBreaker {
+		t.Fatalf("expected %s after hitting MaxConsecutiveLosses, got %s", ReasonCircuitBreaker, reason)
+	}
+}
+
+func TestRecordWinResetsStreak(t *testing.T) {
+	g := newTestGuard(t, Limits{MaxConsecutiveLosses: 2, BreakerCooldown: 0})
+	ctx := context.Background()
+
+	if err := g.RecordLoss(ctx, 1, 10); err != nil {
+		t.Fatalf("record loss: %v", err)
+	}
+	if err := g.RecordWin(ctx, 1); err != nil {
+		t.Fatalf("record win: %v", err)
+	}
+	if err := g.RecordLoss(ctx, 1, 10); err != nil {
+		t.Fatalf("record loss: %v", err)
+	}
+
+	if err := g.Check(ctx, 1, "BTCUSD", 1); err != nil {
+		t.Fatalf("expected streak reset by RecordWin to prevent the breaker tripping, got %v", err)
+	}
+}
+
+func TestRecordLossRejectsOnMaxRoundLoss(t *testing.T) {
+	g := newTestGuard(t, Limits{MaxLossPerRound: 100})
+	ctx := context.Background()
+
+	if err := g.RecordLoss(ctx, 1, 100); err != nil {
+		t.Fatalf("record loss: %v", err)
+	}
+
+	err := g.Check(ctx, 1, "BTCUSD", 1)
+	if reason := rejectReason(t, err); reason != ReasonMaxRoundLoss {
+		t.Fatalf("expected %s, got %s", ReasonMaxRoundLoss, reason)
+	}
+}
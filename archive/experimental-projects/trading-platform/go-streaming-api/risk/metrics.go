@@ -0,0 +1,15 @@
+package risk
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var rejectionsTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "trading_risk_rejections_total",
+		Help: "Total number of orders rejected by risk guardrails, labeled by reason",
+	},
+	[]string{"reason"},
+)
+
+func init() {
+	prometheus.MustRegister(rejectionsTotal)
+}
@@ -0,0 +1,98 @@
+package risk
+
+import "sync"
+
+// position is one user's net exposure in a single symbol, tracked with
+// average-cost accounting: qty is signed (positive long, negative
+// short) and avgPrice is the cost basis of the current side.
+type position struct {
+	qty      float64
+	avgPrice float64
+}
+
+type positionKey struct {
+	userID uint32
+	symbol string
+}
+
+// PositionTracker computes realized P&L per user/symbol using
+// average-cost accounting, so a trade outcome can be folded into
+// Guard's round-loss total and consecutive-loss streak via RecordLoss
+// and RecordWin. It holds no Redis state of its own — positions live
+// for the life of this replica, the same as the matching engine books
+// they shadow.
+type PositionTracker struct {
+	mu        sync.Mutex
+	positions map[positionKey]*position
+}
+
+// NewPositionTracker creates an empty tracker.
+func NewPositionTracker() *PositionTracker {
+	return &PositionTracker{positions: make(map[positionKey]*position)}
+}
+
+// OnFill applies a fill of qty at price on the buy/sell side isBuy for
+// userID in symbol. closed is true if the fill closed out some or all
+// of an existing opposing position, in which case realized is the P&L
+// on the closed portion; closed is false (realized always 0) while the
+// fill only opens or adds to a position.
+func (t *PositionTracker) OnFill(userID uint32, symbol string, isBuy bool, price, qty float64) (realized float64, closed bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := positionKey{userID: userID, symbol: symbol}
+	pos, ok := t.positions[key]
+	if !ok {
+		pos = &position{}
+		t.positions[key] = pos
+	}
+
+	signedQty := qty
+	if !isBuy {
+		signedQty = -qty
+	}
+
+	if pos.qty == 0 || sameSign(pos.qty, signedQty) {
+		// Opening or adding to a position in the same direction: roll
+		// the average entry price forward, nothing realized yet.
+		newQty := pos.qty + signedQty
+		pos.avgPrice = (pos.avgPrice*absFloat(pos.qty) + price*qty) / absFloat(newQty)
+		pos.qty = newQty
+		return 0, false
+	}
+
+	// Closing some or all of an existing opposing position.
+	closingQty := qty
+	if absFloat(pos.qty) < qty {
+		closingQty = absFloat(pos.qty)
+	}
+	if pos.qty > 0 {
+		realized = (price - pos.avgPrice) * closingQty
+	} else {
+		realized = (pos.avgPrice - price) * closingQty
+	}
+
+	newQty := pos.qty + signedQty
+	pos.qty = newQty
+	switch {
+	case newQty == 0:
+		pos.avgPrice = 0
+	case sameSign(newQty, signedQty):
+		// The fill was large enough to flip the position; the leftover
+		// opens a fresh position at this fill's price.
+		pos.avgPrice = price
+	}
+
+	return realized, true
+}
+
+func sameSign(a, b float64) bool {
+	return (a > 0 && b > 0) || (a < 0 && b < 0)
+}
+
+func absFloat(v float64) float64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,65 @@
+package risk
+
+import "testing"
+
+func TestPositionTrackerOpensWithoutRealizing(t *testing.T) {
+	tr := NewPositionTracker()
+
+	realized, closed := tr.OnFill(1, "BTCUSD", true, 100, 5)
+	if closed {
+		t.Fatalf("opening a position should not realize P&L, got realized=%v closed=%v", realized, closed)
+	}
+}
+
+func TestPositionTrackerRealizesOnClose(t *testing.T) {
+	tr := NewPositionTracker()
+
+	tr.OnFill(1, "BTCUSD", true, 100, 5) // long 5 @ 100
+
+	realized, closed := tr.OnFill(1, "BTCUSD", false, 110, 5) // sell 5 @ 110
+	if !closed {
+		t.Fatalf("expected the long position to close")
+	}
+	if realized != 50 {
+		t.Fatalf("expected a 50 profit (5 * (110-100)), got %v", realized)
+	}
+}
+
+func TestPositionTrackerRealizesLossOnClose(t *testing.T) {
+	tr := NewPositionTracker()
+
+	tr.OnFill(1, "BTCUSD", true, 100, 5) // long 5 @ 100
+
+	realized, closed := tr.OnFill(1, "BTCUSD", false, 90, 5) // sell 5 @ 90
+	if !closed {
+		t.Fatalf("expected the long position to close")
+	}
+	if realized != -50 {
+		t.Fatalf("expected a 50 loss (5 * (90-100)), got %v", realized)
+	}
+}
+
+func TestPositionTrackerFlipsAndOpensAtFillPrice(t *testing.T) {
+	tr := NewPositionTracker()
+
+	tr.OnFill(1, "BTCUSD", true, 100, 5) // long 5 @ 100
+
+	realized, closed := tr.OnFill(1, "BTCUSD", false, 110, 8) // sell 8 @ 110: closes 5, opens -3
+	if !closed {
+		t.Fatalf("expected the existing long to close")
+	}
+	if realized != 50 {
+		t.Fatalf("expected P&L on the closed 5 units only, got %v", realized)
+	}
+
+	// The leftover 3-unit short should now be open at the flip price: a
+	// further buy at the same price should realize 0, not re-derive the
+	// old average cost.
+	realized, closed = tr.OnFill(1, "BTCUSD", true, 110, 3)
+	if !closed {
+		t.Fatalf("expected the flipped short to close")
+	}
+	if realized != 0 {
+		t.Fatalf("expected flat P&L closing the flip at its own entry price, got %v", realized)
+	}
+}